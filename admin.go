@@ -0,0 +1,242 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type levelState struct {
+	LogId     string       `json:"logId,omitempty"`
+	MinLevel  MessageLevel `json:"minLevel"`
+	Name      string       `json:"minLevelName"`
+	Inherited bool         `json:"inherited,omitempty"`
+}
+
+type levelsState struct {
+	MinLevel MessageLevel `json:"minLevel"`
+	Name     string       `json:"minLevelName"`
+	Pools    []levelState `json:"pools"`
+}
+
+// levelUpdate is the body of PUT requests. A null minLevel resets [LogPool] to the default of [Kibilog].
+type levelUpdate struct {
+	MinLevel *MessageLevel `json:"minLevel"`
+}
+
+// LevelHandler returns [http.Handler] for viewing and changing minimum levels at runtime.
+//
+// Mount it with [http.StripPrefix], the remaining path selects the target:
+//
+// - GET / returns the default level of [Kibilog] and the levels of all registered [LogPool]
+//
+// - PUT / with {"minLevel": "warning"} changes the default level of [Kibilog]
+//
+// - GET /{logId} returns the level of [LogPool]
+//
+// - PUT /{logId} with {"minLevel": "debug"} changes the level of [LogPool], {"minLevel": null} resets it to the default
+//
+// Levels are accepted as numbers or as names understood by [ParseLevel].
+func (k *Kibilog) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logId := strings.Trim(r.URL.Path, "/")
+
+		var pool *LogPool
+		if logId != "" {
+			var err error
+			pool, err = k.GetLogPoolById(logId)
+			if err != nil {
+				writeLevelError(w, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var update levelUpdate
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				writeLevelError(w, http.StatusBadRequest, err)
+				return
+			}
+			switch {
+			case pool != nil && update.MinLevel == nil:
+				pool.ResetMinLevel()
+			case update.MinLevel == nil:
+				writeLevelError(w, http.StatusBadRequest, fmt.Errorf("The minLevel is required"))
+				return
+			case !update.MinLevel.isValid():
+				writeLevelError(w, http.StatusBadRequest, fmt.Errorf("Unknown message level %d", int(*update.MinLevel)))
+				return
+			case pool != nil:
+				pool.SetMinLevel(*update.MinLevel)
+			default:
+				k.SetMinLevel(*update.MinLevel)
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeLevelError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s is not allowed", r.Method))
+			return
+		}
+
+		if pool != nil {
+			writeLevelJSON(w, poolLevelState(pool))
+			return
+		}
+		state := levelsState{
+			MinLevel: k.GetMinLevel(),
+			Name:     k.GetMinLevel().String(),
+			Pools:    []levelState{},
+		}
+		for _, p := range k.logPools() {
+			state.Pools = append(state.Pools, poolLevelState(p))
+		}
+		writeLevelJSON(w, state)
+	})
+}
+
+func poolLevelState(pool *LogPool) levelState {
+	return levelState{
+		LogId:     pool.GetLogID().String(),
+		MinLevel:  pool.GetMinLevel(),
+		Name:      pool.GetMinLevel().String(),
+		Inherited: pool.minLevel.Load() == nil,
+	}
+}
+
+func writeLevelJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeLevelError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// logPools returns the registered [LogPool] sorted by LogID.
+func (k *Kibilog) logPools() []*LogPool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	pools := make([]*LogPool, 0, len(k.pools))
+	for _, pool := range k.pools {
+		pools = append(pools, pool)
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].GetLogID().String() < pools[j].GetLogID().String()
+	})
+	return pools
+}
+
+// stepLevel moves the level by the number of known levels. Positive steps make it more severe.
+func stepLevel(level MessageLevel, steps int) MessageLevel {
+	rounded := level.Round()
+	for i, known := range levelsAscending {
+		if known == rounded {
+			i += steps
+			if i < 0 {
+				i = 0
+			}
+			if i >= len(levelsAscending) {
+				i = len(levelsAscending) - 1
+			}
+			return levelsAscending[i]
+		}
+	}
+	return level
+}
+
+// levelStepper steps the default minimum level of [Kibilog] and the levels set on registered [LogPool],
+// so pools inheriting the default keep inheriting it. The levels are reverted after a while.
+type levelStepper struct {
+	mu          sync.Mutex
+	k           *Kibilog
+	revertAfter time.Duration
+	// saved holds the levels before the first step, nil if there is nothing to revert.
+	saved *steppedLevels
+	// applied holds the levels set by the last step, levels changed since then are not reverted.
+	applied *steppedLevels
+	timer   *time.Timer
+}
+
+type steppedLevels struct {
+	defaultLevel MessageLevel
+	pools        map[*LogPool]MessageLevel
+}
+
+func (s *levelStepper) step(steps int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.revertAfter > 0 && s.saved == nil {
+		s.saved = &steppedLevels{defaultLevel: s.k.GetMinLevel(), pools: map[*LogPool]MessageLevel{}}
+		s.applied = &steppedLevels{pools: map[*LogPool]MessageLevel{}}
+	}
+
+	level := stepLevel(s.k.GetMinLevel(), steps)
+	s.k.SetMinLevel(level)
+	if s.applied != nil {
+		s.applied.defaultLevel = level
+	}
+	for _, pool := range s.k.logPools() {
+		current := pool.minLevel.Load()
+		if current == nil {
+			continue
+		}
+		if s.saved != nil {
+			if _, ok := s.saved.pools[pool]; !ok {
+				s.saved.pools[pool] = *current
+			}
+		}
+		level := stepLevel(*current, steps)
+		pool.SetMinLevel(level)
+		if s.applied != nil {
+			s.applied.pools[pool] = level
+		}
+	}
+
+	if s.revertAfter > 0 {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		s.timer = time.AfterFunc(s.revertAfter, s.revert)
+	}
+}
+
+func (s *levelStepper) revert() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revertLocked()
+}
+
+// revertLocked restores the saved levels that were not changed by others since the last step.
+func (s *levelStepper) revertLocked() {
+	if s.saved == nil {
+		return
+	}
+	if s.k.GetMinLevel() == s.applied.defaultLevel {
+		s.k.SetMinLevel(s.saved.defaultLevel)
+	}
+	for pool, level := range s.saved.pools {
+		if current := pool.minLevel.Load(); current != nil && *current == s.applied.pools[pool] {
+			pool.SetMinLevel(level)
+		}
+	}
+	s.saved = nil
+	s.applied = nil
+}
+
+// stop cancels the pending revert and restores the levels right away.
+func (s *levelStepper) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.revertLocked()
+}
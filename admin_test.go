@@ -0,0 +1,191 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKibilog_LevelHandler(t *testing.T) {
+	k := GetInstance()
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb47")
+	k.AddLogPool(l)
+	t.Cleanup(func() { k.removeLogPool(l) })
+	defer k.SetMinLevel(0)
+
+	handler := http.StripPrefix("/levels", k.LevelHandler())
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		check      func() bool
+	}{
+		{
+			name:       "set pool level by name",
+			method:     http.MethodPut,
+			path:       "/levels/01hggahp9skcph42wknxbckb47",
+			body:       `{"minLevel": "warn"}`,
+			wantStatus: http.StatusOK,
+			check:      func() bool { return l.GetMinLevel() == LevelWarning },
+		},
+		{
+			name:       "reset pool level",
+			method:     http.MethodPut,
+			path:       "/levels/01hggahp9skcph42wknxbckb47",
+			body:       `{"minLevel": null}`,
+			wantStatus: http.StatusOK,
+			check:      func() bool { return l.minLevel.Load() == nil },
+		},
+		{
+			name:       "set default level by number",
+			method:     http.MethodPut,
+			path:       "/levels/",
+			body:       `{"minLevel": 20}`,
+			wantStatus: http.StatusOK,
+			check:      func() bool { return k.GetMinLevel() == LevelInfo && l.GetMinLevel() == LevelInfo },
+		},
+		{
+			name:       "unknown pool",
+			method:     http.MethodGet,
+			path:       "/levels/01hggahp9skcph42wknxbckb99",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid level",
+			method:     http.MethodPut,
+			path:       "/levels/01hggahp9skcph42wknxbckb47",
+			body:       `{"minLevel": "verbose"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown numeric level",
+			method:     http.MethodPut,
+			path:       "/levels/",
+			body:       `{"minLevel": 9999}`,
+			wantStatus: http.StatusBadRequest,
+			check:      func() bool { return k.GetMinLevel() == LevelInfo },
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodDelete,
+			path:       "/levels",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.path, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Status = %v, want %v. Body: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.check != nil && !tt.check() {
+				t.Errorf("The level was not applied. Body: %s", w.Body.String())
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		w := do(http.MethodGet, "/levels", "")
+		var state levelsState
+		if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		found := false
+		for _, p := range state.Pools {
+			if p.LogId == l.GetLogID().String() {
+				found = p.Inherited && p.MinLevel == k.GetMinLevel()
+			}
+		}
+		if !found {
+			t.Errorf("LogPool is missing in the list: %s", w.Body.String())
+		}
+	})
+}
+
+func Test_stepLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		level MessageLevel
+		steps int
+		want  MessageLevel
+	}{
+		{name: "up", level: LevelInfo, steps: 1, want: LevelNotice},
+		{name: "down", level: LevelInfo, steps: -1, want: LevelDebug},
+		{name: "below debug", level: LevelDebug, steps: -1, want: LevelDebug},
+		{name: "above emergency", level: LevelEmergency, steps: 2, want: LevelEmergency},
+		{name: "everything", level: 0, steps: 1, want: LevelInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stepLevel(tt.level, tt.steps); got != tt.want {
+				t.Errorf("stepLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_levelStepper(t *testing.T) {
+	k := GetInstance()
+	defer k.SetMinLevel(0)
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb48")
+	inherited, _ := NewLogPool("01hggahp9skcph42wknxbckb4a")
+	k.AddLogPool(l)
+	k.AddLogPool(inherited)
+	t.Cleanup(func() { k.removeLogPool(l); k.removeLogPool(inherited) })
+
+	t.Run("revert", func(t *testing.T) {
+		k.SetMinLevel(LevelNotice)
+		l.SetMinLevel(LevelWarning)
+		s := &levelStepper{k: k, revertAfter: time.Hour}
+		defer s.stop()
+		s.step(-1)
+		s.step(-1)
+		if l.GetMinLevel() != LevelInfo || inherited.GetMinLevel() != LevelDebug {
+			t.Errorf("GetMinLevel() after steps = %v, %v, want %v, %v", l.GetMinLevel(), inherited.GetMinLevel(), LevelInfo, LevelDebug)
+		}
+		if inherited.minLevel.Load() != nil {
+			t.Errorf("The step pinned the level of the inheriting pool")
+		}
+
+		s.revert()
+		if l.GetMinLevel() != LevelWarning || k.GetMinLevel() != LevelNotice {
+			t.Errorf("GetMinLevel() after revert = %v, %v, want %v, %v", l.GetMinLevel(), k.GetMinLevel(), LevelWarning, LevelNotice)
+		}
+	})
+
+	t.Run("changed during the window", func(t *testing.T) {
+		k.SetMinLevel(LevelNotice)
+		l.SetMinLevel(LevelWarning)
+		s := &levelStepper{k: k, revertAfter: time.Hour}
+		s.step(1)
+		l.SetMinLevel(LevelDebug)
+		s.stop()
+		if l.GetMinLevel() != LevelDebug {
+			t.Errorf("GetMinLevel() = %v, the level changed during the window was reverted", l.GetMinLevel())
+		}
+		if k.GetMinLevel() != LevelNotice {
+			t.Errorf("Kibilog.GetMinLevel() after stop = %v, want %v", k.GetMinLevel(), LevelNotice)
+		}
+	})
+
+	t.Run("without revert", func(t *testing.T) {
+		k.SetMinLevel(LevelNotice)
+		s := &levelStepper{k: k}
+		s.step(1)
+		k.SetMinLevel(LevelError)
+		if inherited.GetMinLevel() != LevelError {
+			t.Errorf("GetMinLevel() = %v, the inheriting pool does not follow Kibilog.SetMinLevel", inherited.GetMinLevel())
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package gokibilog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// CallerKey is the reserved params key where [LogPool.SetCallerCapture] stores the caller.
+const CallerKey = "_caller"
+
+// maxCallerFrames limits the number of frames looked through to find the caller.
+const maxCallerFrames = 32
+
+// packagePrefix is the prefix of the function names of this package, its frames are never reported as the caller.
+var packagePrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	return name[:slash+strings.Index(name[slash:], ".")+1]
+}()
+
+// callerCapture holds the caller capturing setting of [LogPool], nil while disabled.
+type callerCapture struct {
+	skip int
+}
+
+// SetCallerCapture enables or disables recording the caller file:line and function into the params key [CallerKey].
+//
+// The caller is the first function outside gokibilog. If you wrap [LogPool] with your own helpers,
+// set skip to the number of wrapper frames to skip. Disabled by default, when disabled it costs nothing.
+//
+// Params that are not a map[string]any, for example a struct or a slice, are moved under the "params" key
+// next to [CallerKey], like [Kibilog.SetLenientParams] does.
+func (l *LogPool) SetCallerCapture(enabled bool, skip int) {
+	if !enabled {
+		l.caller.Store(nil)
+		return
+	}
+	l.caller.Store(&callerCapture{skip: skip})
+}
+
+// captureCaller adds the caller to the params of the message if it is enabled.
+func (l *LogPool) captureCaller(m *Message) {
+	capture := l.caller.Load()
+	if capture == nil {
+		return
+	}
+	file, function, ok := findCaller(capture.skip)
+	if !ok {
+		return
+	}
+	caller := map[string]any{
+		"file":     file,
+		"function": function,
+	}
+	params, ok := mergeParams(m.Params, map[string]any{CallerKey: caller})
+	if !ok {
+		params = wrapParams(m.Params, map[string]any{CallerKey: caller})
+	}
+	m.Params = params
+}
+
+// findCaller returns the first frame outside this package, skipping skip more frames after it.
+func findCaller(skip int) (file string, function string, ok bool) {
+	var pc [maxCallerFrames]uintptr
+	n := runtime.Callers(2, pc[:])
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if !isPackageFrame(frame) {
+			if skip == 0 {
+				return fmt.Sprintf("%s:%d", frame.File, frame.Line), frame.Function, true
+			}
+			skip--
+		}
+		if !more {
+			return "", "", false
+		}
+	}
+}
+
+func isPackageFrame(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, packagePrefix) && !strings.HasSuffix(frame.File, "_test.go")
+}
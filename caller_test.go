@@ -0,0 +1,86 @@
+package gokibilog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func logThroughWrapper(l *LogPool) {
+	l.Info("wrapped")
+}
+
+func TestLogPool_SetCallerCapture(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		skip         int
+		log          func(l *LogPool)
+		wantFunction string
+		wantParams   any
+	}{
+		{
+			name:    "disabled",
+			enabled: false,
+			log:     func(l *LogPool) { l.Info("test") },
+		},
+		{
+			name:         "direct",
+			enabled:      true,
+			log:          func(l *LogPool) { l.Logger().With("userId", 1).Info("test") },
+			wantFunction: "TestLogPool_SetCallerCapture.func",
+		},
+		{
+			name:         "wrapper skipped",
+			enabled:      true,
+			skip:         1,
+			log:          func(l *LogPool) { logThroughWrapper(l) },
+			wantFunction: "TestLogPool_SetCallerCapture.func",
+		},
+		{
+			name:         "wrapper not skipped",
+			enabled:      true,
+			log:          func(l *LogPool) { logThroughWrapper(l) },
+			wantFunction: "logThroughWrapper",
+		},
+		{
+			name:    "struct params",
+			enabled: true,
+			log: func(l *LogPool) {
+				m, _ := NewMessage("test", LevelInfo)
+				m.SetParams([]int{1, 2})
+				l.AddMessage(m)
+			},
+			wantFunction: "TestLogPool_SetCallerCapture.func",
+			wantParams:   []int{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+			l.SetCallerCapture(tt.enabled, tt.skip)
+			tt.log(l)
+
+			params, _ := l.messages[0].Params.(map[string]any)
+			caller, ok := params[CallerKey].(map[string]any)
+			if !tt.enabled {
+				if ok {
+					t.Errorf("Caller was captured while disabled: %v", caller)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Caller is missing in params: %v", params)
+			}
+			if !strings.Contains(caller["function"].(string), tt.wantFunction) {
+				t.Errorf("function = %v, want %v", caller["function"], tt.wantFunction)
+			}
+			if !strings.Contains(caller["file"].(string), "caller_test.go:") {
+				t.Errorf("file = %v, want caller_test.go", caller["file"])
+			}
+			if tt.wantParams != nil && !reflect.DeepEqual(params["params"], tt.wantParams) {
+				t.Errorf("params = %v, want the original params under \"params\"", params)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package gokibilog
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// maxCauses limits the number of causes collected by [CaptureError], protecting from cyclic error chains.
+const maxCauses = 64
+
+// maxStackFrames limits the depth of the stack collected by [CaptureError].
+const maxStackFrames = 64
+
+// CaptureOptions configures [CaptureError].
+type CaptureOptions struct {
+	// Level of the message. [LevelError] if zero.
+	Level MessageLevel
+	// Message is the text of the message. The text of the error if empty.
+	Message string
+	// Params are added to the params of the message.
+	Params map[string]any
+	// Skip is the number of additional stack frames to skip, for wrappers around CaptureError.
+	Skip int
+}
+
+// CaptureError adds a message about the error to [LogPool].
+//
+// Error types rarely have exported fields and are encoded as {} in params, so the params get:
+// "error" - the text, "errorType" - the concrete type, "causes" - the tree of errors.Unwrap and errors.Join
+// flattened in depth-first order, "stack" - the stack of the caller.
+func CaptureError(pool *LogPool, err error, opts CaptureOptions) {
+	captureError(context.Background(), pool, err, opts)
+}
+
+// CaptureErrorContext works like [CaptureError] and applies the partition and fields of ctx.
+func CaptureErrorContext(ctx context.Context, pool *LogPool, err error, opts CaptureOptions) {
+	captureError(ctx, pool, err, opts)
+}
+
+func captureError(ctx context.Context, pool *LogPool, err error, opts CaptureOptions) {
+	if err == nil {
+		return
+	}
+	level := opts.Level
+	if level == 0 {
+		level = LevelError
+	}
+	if !pool.Enabled(level) {
+		return
+	}
+	text := opts.Message
+	if text == "" {
+		text = err.Error()
+	}
+
+	params := make(map[string]any, len(opts.Params)+4)
+	for k, v := range opts.Params {
+		params[k] = v
+	}
+	params["error"] = err.Error()
+	params["errorType"] = fmt.Sprintf("%T", err)
+	if causes := errorCauses(err); len(causes) > 0 {
+		params["causes"] = causes
+	}
+	// Skip runtime.Callers, callerStack, captureError and CaptureError.
+	params["stack"] = callerStack(4 + opts.Skip)
+
+	if m := pool.newMessage(level, text, nil); m != nil {
+		m.SetParams(params)
+		pool.contextLogger(ctx).AddMessage(m)
+	}
+}
+
+// errorCauses walks errors.Unwrap and errors.Join trees below err in depth-first order.
+func errorCauses(err error) []map[string]any {
+	var causes []map[string]any
+	var walk func(err error, depth int)
+	walk = func(err error, depth int) {
+		var children []error
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			children = e.Unwrap()
+		case interface{ Unwrap() error }:
+			children = []error{e.Unwrap()}
+		}
+		for _, child := range children {
+			if child == nil || len(causes) >= maxCauses {
+				continue
+			}
+			causes = append(causes, map[string]any{
+				"message": child.Error(),
+				"type":    fmt.Sprintf("%T", child),
+				"depth":   depth,
+			})
+			walk(child, depth+1)
+		}
+	}
+	walk(err, 1)
+	return causes
+}
+
+// callerStack returns the stack as "function file:line" strings, skipping the first frames.
+func callerStack(skip int) []string {
+	pc := make([]uintptr, maxStackFrames)
+	n := runtime.Callers(skip, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	stack := make([]string, 0, n)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+	return stack
+}
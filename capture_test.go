@@ -0,0 +1,82 @@
+package gokibilog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_errorCauses(t *testing.T) {
+	_, pathErr := os.Open("/not/exists")
+	tests := []struct {
+		name      string
+		err       error
+		wantTypes []string
+		wantDepth []int
+	}{
+		{
+			name: "no causes",
+			err:  errors.New("boom"),
+		},
+		{
+			name:      "wrapped",
+			err:       fmt.Errorf("load config: %w", pathErr),
+			wantTypes: []string{"*fs.PathError", "syscall.Errno"},
+			wantDepth: []int{1, 2},
+		},
+		{
+			name:      "joined",
+			err:       errors.Join(errors.New("first"), fmt.Errorf("second: %w", fs.ErrNotExist)),
+			wantTypes: []string{"*errors.errorString", "*fmt.wrapError", "*errors.errorString"},
+			wantDepth: []int{1, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			causes := errorCauses(tt.err)
+			if len(causes) != len(tt.wantTypes) {
+				t.Fatalf("errorCauses() = %v, want types %v", causes, tt.wantTypes)
+			}
+			for i, cause := range causes {
+				if cause["type"] != tt.wantTypes[i] || cause["depth"] != tt.wantDepth[i] {
+					t.Errorf("cause %d = %v, want type %v, depth %v", i, cause, tt.wantTypes[i], tt.wantDepth[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCaptureError(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+		CaptureError(l, fmt.Errorf("load config: %w", fs.ErrNotExist), CaptureOptions{Params: map[string]any{"file": "app.yaml"}})
+
+		if l.Len() != 1 {
+			t.Fatalf("Messages count = %v, want 1", l.Len())
+		}
+		m := l.messages[0]
+		params := m.Params.(map[string]any)
+		if m.Level != LevelError || m.Message != "load config: file does not exist" {
+			t.Errorf("Message = %v, level %v", m.Message, m.Level)
+		}
+		if params["errorType"] != "*fmt.wrapError" || params["file"] != "app.yaml" || len(params["causes"].([]map[string]any)) != 1 {
+			t.Errorf("Params = %v", params)
+		}
+		if stack := params["stack"].([]string); !strings.Contains(stack[0], "TestCaptureError") {
+			t.Errorf("The first stack frame = %v, want the caller", stack[0])
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+		CaptureError(l, errors.New("boom"), CaptureOptions{Level: LevelCritical, Message: "Payment failed"})
+		CaptureError(l, nil, CaptureOptions{})
+
+		if l.Len() != 1 || l.messages[0].Level != LevelCritical || l.messages[0].Message != "Payment failed" {
+			t.Errorf("Messages = %+v", l.messages)
+		}
+	})
+}
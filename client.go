@@ -2,6 +2,7 @@ package gokibilog
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,54 +10,133 @@ import (
 	"time"
 )
 
+// APIError is returned when Kibilog.com responds with a status other than 200.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Kibilog.com returned the %v status code. Response: %s", e.StatusCode, e.Body)
+}
+
+// Rejected reports whether Kibilog.com refused the messages themselves, so sending them again is pointless.
+func (e *APIError) Rejected() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusRequestTimeout && e.StatusCode != http.StatusTooManyRequests
+}
+
+// sendTimeout limits a request to Kibilog.com, so a hung server cannot stall sending forever.
+const sendTimeout = 30 * time.Second
+
 type client struct {
 	baseUrl   string
 	authToken string
+	skew      skewTracker
 }
 
 func (c *client) SetToken(token string) {
 	c.authToken = token
 }
 
-func (c *client) Send(logPool *LogPool) error {
+// droppedMessage is a message that could not be encoded or exceeded [Kibilog.SetMaxMessageBytes] after the enrichment.
+type droppedMessage struct {
+	message *Message
+	reason  DeadLetterReason
+	err     error
+}
+
+// Send uploads the messages of [LogPool] with the default params added. The messages are not modified.
+//
+// Every message is encoded on its own, after the default params are added. Messages that cannot be encoded
+// or exceed the size limit are not sent and are returned as dropped. If none is left, no request is made.
+func (c *client) Send(ctx context.Context, logPool *LogPool, messages []*Message, defaults map[string]any) (dropped []droppedMessage, err error) {
+	original := messages
 	httpClient := &http.Client{
 		Transport: &http.Transport{
 			IdleConnTimeout: 3 * time.Second,
 		},
+		Timeout: sendTimeout,
 	}
 
-	body, err := json.Marshal(logPool.messages)
-	if err != nil {
-		return err
+	messages = withEncodedParams(messages)
+	messages = withDefaultParams(messages, defaults)
+	if flatten := GetInstance().flatten.Load(); flatten != nil {
+		messages = withFlattenedParams(messages, *flatten)
+	}
+	skew, ok := c.skew.correction()
+	if !ok {
+		skew = 0
+	}
+	messages = correctMessages(messages, skew, GetInstance().GetTimePrecision())
+
+	maxBytes := GetInstance().maxMessageBytes.Load()
+	body := []byte{'['}
+	for i, m := range messages {
+		encoded, err := json.Marshal(m)
+		if err != nil {
+			dropped = append(dropped, droppedMessage{
+				message: original[i],
+				reason:  DeadLetterValidation,
+				err: &ValidationError{Fields: []*FieldError{{
+					Field: "params",
+					Err:   fmt.Errorf("%w: %s", ErrUnencodableParams, err.Error()),
+				}}},
+			})
+			continue
+		}
+		if maxBytes > 0 && int64(len(encoded)) > maxBytes {
+			dropped = append(dropped, droppedMessage{
+				message: original[i],
+				reason:  DeadLetterOversize,
+				err: &ValidationError{Fields: []*FieldError{{
+					Field: "message",
+					Err:   fmt.Errorf("%w: %d bytes with the default params, the limit is %d", ErrOversize, len(encoded), maxBytes),
+				}}},
+			})
+			continue
+		}
+		if len(body) > 1 {
+			body = append(body, ',')
+		}
+		body = append(body, encoded...)
+	}
+	body = append(body, ']')
+	if len(dropped) == len(messages) {
+		return dropped, nil
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
-		fmt.Sprintf("%s/%s", c.baseUrl, logPool.getLogId()),
+		fmt.Sprintf("%s/%s", c.baseUrl, logPool.GetLogID()),
 		bytes.NewReader(body),
 	)
 	if err != nil {
-		return err
+		return dropped, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("apiToken", c.authToken)
 
+	clock := GetInstance().GetClock()
+	sentAt := clock.Now()
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return err
+		return dropped, err
 	}
 	defer resp.Body.Close()
+	c.skew.observe(resp.Header, sentAt, clock.Now())
 
 	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return dropped, err
 	}
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("Kibilog.com returned the %v status code. Response: %s", resp.StatusCode, string(body))
+		return dropped, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	return nil
+	return dropped, nil
 }
 
 var clientInstance *client
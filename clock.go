@@ -0,0 +1,52 @@
+package gokibilog
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of the current time used by [Kibilog] to stamp messages.
+//
+// Use [Kibilog.SetClock] to replace it, for example, with [ManualClock] in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock is a [Clock] that only moves when it is told to.
+// It allows tests to freeze and advance time deterministically.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to the passed time.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by the passed duration.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// NewManualClock create new [ManualClock] frozen at the passed time
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
@@ -0,0 +1,56 @@
+package gokibilog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 01, 30, 15, 16, 59, 0, time.UTC)
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    time.Time
+	}{
+		{
+			name:    "frozen",
+			advance: 0,
+			want:    start,
+		},
+		{
+			name:    "advanced",
+			advance: 1500 * time.Millisecond,
+			want:    start.Add(1500 * time.Millisecond),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewManualClock(start)
+			c.Advance(tt.advance)
+			if got := c.Now(); !got.Equal(tt.want) {
+				t.Errorf("Now() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKibilog_SetClock(t *testing.T) {
+	k := GetInstance()
+	defer k.SetClock(nil)
+
+	clock := NewManualClock(time.Date(2024, 01, 30, 15, 16, 59, 0, time.UTC))
+	k.SetClock(clock)
+
+	m1, _ := NewMessage("test 1", LevelDebug)
+	clock.Advance(10 * time.Second)
+	m2, _ := NewMessage("test 2", LevelDebug)
+
+	if *m2.CreatedAt-*m1.CreatedAt != 10 {
+		t.Errorf("CreatedAt difference = %v, want 10", *m2.CreatedAt-*m1.CreatedAt)
+	}
+
+	k.SetClock(nil)
+	if _, ok := k.GetClock().(systemClock); !ok {
+		t.Errorf("SetClock(nil) = %T, want systemClock", k.GetClock())
+	}
+}
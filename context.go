@@ -0,0 +1,106 @@
+package gokibilog
+
+import (
+	"context"
+)
+
+type contextKey int
+
+const (
+	partitionContextKey contextKey = iota
+	fieldsContextKey
+)
+
+// ContextWithPartition returns a copy of ctx carrying the partition.
+// The partition is converted by [ToPartition], nil removes the partition of the parent ctx.
+func ContextWithPartition(ctx context.Context, partition any) (context.Context, error) {
+	p, err := ToPartition(partition)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, partitionContextKey, p), nil
+}
+
+// PartitionFromContext returns the partition stored by [ContextWithPartition].
+func PartitionFromContext(ctx context.Context) (partition Partition, ok bool) {
+	p, _ := ctx.Value(partitionContextKey).(*Partition)
+	if p == nil {
+		return Partition{}, false
+	}
+	return *p, true
+}
+
+// ContextWithFields returns a copy of ctx carrying params that are added to messages logged with it.
+// keyvals are alternating keys and values, they are merged with the fields already stored in ctx.
+func ContextWithFields(ctx context.Context, keyvals ...any) context.Context {
+	fields := FieldsFromContext(ctx)
+	for k, v := range keyvalsToParams(keyvals) {
+		fields[k] = v
+	}
+	return context.WithValue(ctx, fieldsContextKey, fields)
+}
+
+// FieldsFromContext returns a copy of the fields stored by [ContextWithFields].
+func FieldsFromContext(ctx context.Context) map[string]any {
+	stored, _ := ctx.Value(fieldsContextKey).(map[string]any)
+	fields := make(map[string]any, len(stored))
+	for k, v := range stored {
+		fields[k] = v
+	}
+	return fields
+}
+
+// contextLogger returns [Logger] with the partition and fields of ctx.
+func (l *LogPool) contextLogger(ctx context.Context) *Logger {
+	g := &Logger{pool: l, params: FieldsFromContext(ctx)}
+	if partition, ok := PartitionFromContext(ctx); ok {
+		g.partition = &partition
+	}
+	return g
+}
+
+// AddMessageContext adds the message like [LogPool.AddMessage] applying the partition and fields of ctx
+// like [Logger.AddMessage].
+func (l *LogPool) AddMessageContext(ctx context.Context, message *Message) {
+	l.contextLogger(ctx).AddMessage(message)
+}
+
+// DebugContext adds a [LevelDebug] message with the partition and fields of ctx.
+func (l *LogPool) DebugContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelDebug, message, keyvals)
+}
+
+// InfoContext adds a [LevelInfo] message with the partition and fields of ctx.
+func (l *LogPool) InfoContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelInfo, message, keyvals)
+}
+
+// NoticeContext adds a [LevelNotice] message with the partition and fields of ctx.
+func (l *LogPool) NoticeContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelNotice, message, keyvals)
+}
+
+// WarningContext adds a [LevelWarning] message with the partition and fields of ctx.
+func (l *LogPool) WarningContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelWarning, message, keyvals)
+}
+
+// ErrorContext adds a [LevelError] message with the partition and fields of ctx.
+func (l *LogPool) ErrorContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelError, message, keyvals)
+}
+
+// CriticalContext adds a [LevelCritical] message with the partition and fields of ctx.
+func (l *LogPool) CriticalContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelCritical, message, keyvals)
+}
+
+// AlertContext adds a [LevelAlert] message with the partition and fields of ctx.
+func (l *LogPool) AlertContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelAlert, message, keyvals)
+}
+
+// EmergencyContext adds a [LevelEmergency] message with the partition and fields of ctx.
+func (l *LogPool) EmergencyContext(ctx context.Context, message string, keyvals ...any) {
+	l.contextLogger(ctx).log(LevelEmergency, message, keyvals)
+}
@@ -0,0 +1,88 @@
+package gokibilog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestContextWithPartition(t *testing.T) {
+	tests := []struct {
+		name      string
+		partition any
+		want      string
+		wantOk    bool
+		wantErr   bool
+	}{
+		{
+			name:      "uuid",
+			partition: "1EC9414C-232A-6B00-B3C8-9E6BDECED846",
+			want:      "1ec9414c-232a-6b00-b3c8-9e6bdeced846",
+			wantOk:    true,
+		},
+		{
+			name:      "nil",
+			partition: nil,
+		},
+		{
+			name:      "invalid",
+			partition: "not uuid",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := ContextWithPartition(context.Background(), tt.partition)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ContextWithPartition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, ok := PartitionFromContext(ctx)
+			if ok != tt.wantOk || ok && got.String() != tt.want {
+				t.Errorf("PartitionFromContext() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestContextWithFields(t *testing.T) {
+	parent := ContextWithFields(context.Background(), "userId", 1, "route", "/api")
+	child := ContextWithFields(parent, "userId", 2)
+
+	if got := FieldsFromContext(child); !reflect.DeepEqual(got, map[string]any{"userId": 2, "route": "/api"}) {
+		t.Errorf("FieldsFromContext(child) = %v", got)
+	}
+	if got := FieldsFromContext(parent); !reflect.DeepEqual(got, map[string]any{"userId": 1, "route": "/api"}) {
+		t.Errorf("FieldsFromContext(parent) = %v", got)
+	}
+}
+
+func TestLogPool_InfoContext(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	ctx, _ := ContextWithPartition(context.Background(), "550e8400-e29b-11d4-a716-446655440000")
+	ctx = ContextWithFields(ctx, "userId", 1)
+
+	l.InfoContext(ctx, "test", "status", "sent")
+	m, _ := NewMessage("test", LevelInfo)
+	l.AddMessageContext(context.Background(), m)
+
+	if l.Len() != 2 {
+		t.Fatalf("Messages count = %v, want 2", l.Len())
+	}
+	got := l.messages[0]
+	if got.Partition == nil || got.Partition.String() != "550e8400-e29b-11d4-a716-446655440000" {
+		t.Errorf("Partition = %v", got.Partition)
+	}
+	if !reflect.DeepEqual(got.Params, map[string]any{"userId": 1, "status": "sent"}) {
+		t.Errorf("Params = %v", got.Params)
+	}
+	if l.messages[1].Partition != nil || l.messages[1].Params != nil {
+		t.Errorf("Message without context values = %#v", l.messages[1])
+	}
+
+	m, _ = NewMessage("struct params", LevelInfo)
+	m.SetParams(struct{ OrderID int }{OrderID: 7})
+	l.AddMessageContext(ctx, m)
+	if !reflect.DeepEqual(m.Params, map[string]any{"userId": 1, "OrderID": 7}) {
+		t.Errorf("Struct params = %v, the fields of ctx are missing", m.Params)
+	}
+}
@@ -0,0 +1,232 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// DeadLetterReason is the reason why a message was dropped.
+type DeadLetterReason string
+
+// Reasons of dropping messages.
+const (
+	// DeadLetterValidation - the message cannot be encoded.
+	DeadLetterValidation DeadLetterReason = "validation"
+	// DeadLetterOversize - the encoded message is larger than [Kibilog.SetMaxMessageBytes].
+	DeadLetterOversize DeadLetterReason = "oversize"
+	// DeadLetterRejected - Kibilog.com rejected the batch with a 4xx status.
+	DeadLetterRejected DeadLetterReason = "api_rejection"
+	// DeadLetterOverflow - [LogPool] was full, see [LogPool.SetMaxMessages].
+	DeadLetterOverflow DeadLetterReason = "overflow"
+	// DeadLetterExpired - the message was older than [LogPool.SetMessageTTL].
+	DeadLetterExpired DeadLetterReason = "expired"
+)
+
+// DeadLetter is a dropped message with the reason.
+type DeadLetter struct {
+	LogId     LogID            `json:"logId"`
+	Reason    DeadLetterReason `json:"reason"`
+	Error     string           `json:"error,omitempty"`
+	DroppedAt time.Time        `json:"droppedAt"`
+	Message   *Message         `json:"message"`
+	// Precision is the unit of Message.CreatedAt, it is restored by [Kibilog.Resubmit].
+	Precision TimePrecision `json:"precision"`
+}
+
+// DeadLetterHandler receives every dropped message. It is called synchronously, so it should be fast.
+// It is never called while [LogPool] is locked, so it may add messages, for example by [Kibilog.Resubmit].
+type DeadLetterHandler func(letter DeadLetter)
+
+// SetDeadLetterHandler sets [DeadLetterHandler]. Passing nil disables it.
+//
+// While the handler is set, batches rejected by Kibilog.com with a 4xx status are removed from [LogPool]
+// and passed to the handler. Otherwise, they stay in [LogPool] and are sent again with the next [Kibilog.SendMessages].
+func (k *Kibilog) SetDeadLetterHandler(handler DeadLetterHandler) {
+	if handler == nil {
+		k.deadLetters.Store(nil)
+		return
+	}
+	k.deadLetters.Store(&handler)
+}
+
+func (k *Kibilog) hasDeadLetterHandler() bool {
+	return k.deadLetters.Load() != nil
+}
+
+// pendingLetter is a message dropped while [LogPool] was locked, it is passed to [DeadLetterHandler] after unlocking.
+type pendingLetter struct {
+	message *Message
+	reason  DeadLetterReason
+	err     error
+}
+
+// deadLetterAll passes the collected messages to [DeadLetterHandler]. The pool must not be locked.
+func (k *Kibilog) deadLetterAll(logId LogID, letters []pendingLetter) {
+	for _, letter := range letters {
+		k.deadLetter(logId, letter.message, letter.reason, letter.err)
+	}
+}
+
+// deadLetter passes the dropped message to [DeadLetterHandler] if it is set.
+func (k *Kibilog) deadLetter(logId LogID, m *Message, reason DeadLetterReason, err error) {
+	handler := k.deadLetters.Load()
+	if handler == nil || m == nil {
+		return
+	}
+	letter := DeadLetter{
+		LogId:     logId,
+		Reason:    reason,
+		DroppedAt: k.GetClock().Now(),
+		Message:   m,
+		Precision: m.precision,
+	}
+	if err != nil {
+		letter.Error = err.Error()
+	}
+	(*handler)(letter)
+}
+
+// Resubmit adds the messages of the dead letters back to the registered [LogPool] with their LogID.
+// It returns an error for every letter whose [LogPool] is not registered.
+func (k *Kibilog) Resubmit(letters []DeadLetter) (errs []error) {
+	for _, letter := range letters {
+		pool, err := k.getLogPool(letter.LogId)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if letter.Message != nil {
+			letter.Message.precision = letter.Precision
+		}
+		pool.AddMessage(letter.Message)
+	}
+	return errs
+}
+
+// DeadLetterQueue keeps dead letters in memory for inspection and resubmitting.
+// Use its Handle method as [DeadLetterHandler].
+type DeadLetterQueue struct {
+	mu      sync.Mutex
+	limit   int
+	letters []DeadLetter
+}
+
+// NewDeadLetterQueue create new [DeadLetterQueue] that keeps up to limit latest letters. 0 means no limit.
+func NewDeadLetterQueue(limit int) *DeadLetterQueue {
+	return &DeadLetterQueue{limit: limit}
+}
+
+// Handle adds the letter to the queue.
+func (q *DeadLetterQueue) Handle(letter DeadLetter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.letters = append(q.letters, letter)
+	if q.limit > 0 && len(q.letters) > q.limit {
+		q.letters = q.letters[len(q.letters)-q.limit:]
+	}
+}
+
+// Letters returns a copy of the letters in the queue.
+func (q *DeadLetterQueue) Letters() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]DeadLetter{}, q.letters...)
+}
+
+// Drain returns the letters and empties the queue, for example before [Kibilog.Resubmit].
+func (q *DeadLetterQueue) Drain() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	letters := q.letters
+	q.letters = nil
+	return letters
+}
+
+// JSONLinesDeadLetters returns [DeadLetterHandler] that writes every letter as a JSON line,
+// for example to a local file. Write errors are passed to the [ErrorHandler] of [Kibilog].
+func JSONLinesDeadLetters(w io.Writer) DeadLetterHandler {
+	var mu sync.Mutex
+	return func(letter DeadLetter) {
+		mu.Lock()
+		defer mu.Unlock()
+		line, err := json.Marshal(letter)
+		if err != nil && letter.Message != nil {
+			// Only the params can fail to encode, the other fields are kept for the re-submission.
+			m := *letter.Message
+			sanitizeMessage(&m)
+			letter.Message = &m
+			line, err = json.Marshal(letter)
+		}
+		if err == nil {
+			_, err = w.Write(append(line, '\n'))
+		}
+		if err != nil {
+			GetInstance().handleError(err)
+		}
+	}
+}
+
+// ReadJSONLinesDeadLetters reads letters written by [JSONLinesDeadLetters].
+//
+// Unknown numeric levels are kept, so messages dead-lettered for them can be fixed and re-submitted.
+// Letters that cannot be decoded are skipped, their errors are joined into the returned error.
+func ReadJSONLinesDeadLetters(r io.Reader) ([]DeadLetter, error) {
+	var letters []DeadLetter
+	var errs []error
+	decoder := json.NewDecoder(r)
+	for {
+		var line json.RawMessage
+		err := decoder.Decode(&line)
+		if err == io.EOF {
+			return letters, errors.Join(errs...)
+		}
+		if err != nil {
+			return letters, errors.Join(append(errs, err)...)
+		}
+		letter, err := decodeDeadLetter(line)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		letters = append(letters, letter)
+	}
+}
+
+// storedDeadLetter decodes a line of [JSONLinesDeadLetters], its Message shadows the one of [DeadLetter].
+type storedDeadLetter struct {
+	DeadLetter
+	Message *storedMessage `json:"message"`
+}
+
+// storedMessage decodes [Message] keeping the level as a raw number, unlike [MessageLevel.UnmarshalJSON].
+type storedMessage struct {
+	*Message
+	Level json.RawMessage `json:"level"`
+}
+
+func decodeDeadLetter(line []byte) (DeadLetter, error) {
+	stored := storedDeadLetter{}
+	if err := json.Unmarshal(line, &stored); err != nil {
+		return DeadLetter{}, err
+	}
+	letter := stored.DeadLetter
+	if stored.Message == nil {
+		return letter, nil
+	}
+	letter.Message = stored.Message.Message
+	if letter.Message == nil {
+		letter.Message = &Message{}
+	}
+	if len(stored.Message.Level) > 0 {
+		var n int
+		if err := json.Unmarshal(stored.Message.Level, &n); err == nil {
+			letter.Message.Level = MessageLevel(n)
+		} else if err := json.Unmarshal(stored.Message.Level, &letter.Message.Level); err != nil {
+			return DeadLetter{}, err
+		}
+	}
+	return letter, nil
+}
@@ -0,0 +1,235 @@
+package gokibilog
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestKibilog_SetDeadLetterHandler(t *testing.T) {
+	k := GetInstance()
+	queue := NewDeadLetterQueue(0)
+	k.SetDeadLetterHandler(queue.Handle)
+	defer k.SetDeadLetterHandler(nil)
+
+	tests := []struct {
+		name       string
+		prepare    func(l *LogPool)
+		status     int
+		wantReason DeadLetterReason
+		wantLeft   int
+	}{
+		{
+			name: "validation",
+			prepare: func(l *LogPool) {
+				m, _ := NewMessage("test", LevelInfo)
+				m.SetParams(map[string]any{"ch": make(chan int)})
+				l.AddMessage(m)
+			},
+			wantReason: DeadLetterValidation,
+		},
+		{
+			name: "params changed after add",
+			prepare: func(l *LogPool) {
+				m, _ := NewMessage("test", LevelInfo)
+				l.AddMessage(m)
+				l.Info("valid")
+				m.SetParams(map[string]any{"ch": make(chan int)})
+			},
+			wantReason: DeadLetterValidation,
+		},
+		{
+			name: "oversize",
+			prepare: func(l *LogPool) {
+				k.SetMaxMessageBytes(10)
+				m, _ := NewMessage("test", LevelInfo)
+				l.AddMessage(m)
+			},
+			wantReason: DeadLetterOversize,
+		},
+		{
+			name: "overflow",
+			prepare: func(l *LogPool) {
+				l.SetMaxMessages(1)
+				l.Info("first")
+				l.Info("second")
+			},
+			wantReason: DeadLetterOverflow,
+		},
+		{
+			name: "expired",
+			prepare: func(l *LogPool) {
+				l.SetMessageTTL(time.Minute)
+				m, _ := NewMessage("test", LevelInfo)
+				m.SetCreatedAt(time.Now().Add(-time.Hour))
+				l.AddMessage(m)
+			},
+			wantReason: DeadLetterExpired,
+		},
+		{
+			name: "api rejection",
+			prepare: func(l *LogPool) {
+				l.Info("test")
+			},
+			status:     http.StatusUnprocessableEntity,
+			wantReason: DeadLetterRejected,
+		},
+		{
+			name: "server error is retained",
+			prepare: func(l *LogPool) {
+				l.Info("test")
+			},
+			status:   http.StatusBadGateway,
+			wantLeft: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			fakeKibilogWithStatus(t, status)
+			defer func() {
+				k.SetMaxMessageBytes(0)
+				queue.Drain()
+			}()
+
+			l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+			tt.prepare(l)
+			k.sendPool(context.Background(), l)
+
+			letters := queue.Letters()
+			if tt.wantReason == "" {
+				if len(letters) != 0 {
+					t.Errorf("Dead letters = %+v, want none", letters)
+				}
+			} else if len(letters) != 1 || letters[0].Reason != tt.wantReason || letters[0].LogId != l.GetLogID() {
+				t.Errorf("Dead letters = %+v, want one with reason %v", letters, tt.wantReason)
+			}
+			if l.Len() != tt.wantLeft {
+				t.Errorf("Messages left = %v, want %v", l.Len(), tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestJSONLinesDeadLetters(t *testing.T) {
+	var buf bytes.Buffer
+	handler := JSONLinesDeadLetters(&buf)
+	m, _ := NewMessage("test", LevelWarning)
+	handler(DeadLetter{LogId: MustParseLogID("01hggahp9skcph42wknxbckb49"), Reason: DeadLetterRejected, Message: m})
+
+	letters, err := ReadJSONLinesDeadLetters(&buf)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if len(letters) != 1 || letters[0].Message.Message != "test" || letters[0].Message.Level != LevelWarning {
+		t.Fatalf("ReadJSONLinesDeadLetters() = %+v", letters)
+	}
+
+	k := GetInstance()
+	if errs := k.Resubmit(letters); len(errs) != 1 {
+		t.Errorf("Resubmit() to an unregistered LogPool errors = %v, want 1", errs)
+	}
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb49")
+	k.AddLogPool(l)
+	t.Cleanup(func() { k.removeLogPool(l) })
+	if errs := k.Resubmit(letters); len(errs) != 0 || l.Len() != 1 {
+		t.Errorf("Resubmit() errors = %v, messages count %v", errs, l.Len())
+	}
+}
+
+func TestJSONLinesDeadLetters_validation(t *testing.T) {
+	var buf bytes.Buffer
+	k := GetInstance()
+	k.SetDeadLetterHandler(JSONLinesDeadLetters(&buf))
+	defer k.SetDeadLetterHandler(nil)
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb49")
+	l.SetErrorHandler(func(err error) {})
+
+	m, _ := NewMessage("unknown level", LevelInfo)
+	m.Level = 99
+	l.AddMessage(m)
+	buf.WriteString(`{"reason":"validation","message":{"message":"bad partition","partition":"nope"}}` + "\n")
+	k.SetMaxMessageBytes(10)
+	defer k.SetMaxMessageBytes(0)
+	l.Info("oversize")
+
+	letters, err := ReadJSONLinesDeadLetters(&buf)
+	if err == nil {
+		t.Errorf("ReadJSONLinesDeadLetters() error = nil, want the error of the bad line")
+	}
+	if len(letters) != 2 {
+		t.Fatalf("ReadJSONLinesDeadLetters() = %+v, want 2 letters", letters)
+	}
+	if letters[0].Reason != DeadLetterValidation || letters[0].Message.Level != 99 || letters[0].Message.Message != "unknown level" {
+		t.Errorf("Letter = %+v, message %+v, want the unknown level kept", letters[0], letters[0].Message)
+	}
+	if letters[1].Reason != DeadLetterOversize || letters[1].Message.Message != "oversize" {
+		t.Errorf("Letter after the bad line = %+v", letters[1])
+	}
+}
+
+func TestJSONLinesDeadLetters_unencodable(t *testing.T) {
+	var buf bytes.Buffer
+	handler := JSONLinesDeadLetters(&buf)
+	m, _ := NewMessage("test", LevelWarning)
+	m.SetCreatedAt(time.Date(2024, 01, 30, 15, 16, 59, 0, time.UTC))
+	m.SetPartition("550e8400-e29b-11d4-a716-446655440000")
+	m.SetParams(map[string]any{"orderId": 7, "ch": make(chan int)})
+	handler(DeadLetter{LogId: MustParseLogID("01hggahp9skcph42wknxbckb49"), Reason: DeadLetterValidation, Message: m})
+
+	letters, err := ReadJSONLinesDeadLetters(&buf)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("ReadJSONLinesDeadLetters() = %+v, %v", letters, err)
+	}
+	got := letters[0].Message
+	if got.CreatedAt == nil || *got.CreatedAt != *m.CreatedAt || got.Partition == nil || *got.Partition != *m.Partition {
+		t.Errorf("Message = %+v, want CreatedAt and Partition kept", got)
+	}
+	params, _ := got.Params.(map[string]any)
+	if params["orderId"] != float64(7) || params[SanitizedKey] == nil {
+		t.Errorf("Params = %v, want the encodable params kept and the replaced ones listed", got.Params)
+	}
+	if _, ok := m.Params.(map[string]any)[SanitizedKey]; ok {
+		t.Errorf("The handler modified the params of the message: %v", m.Params)
+	}
+}
+
+func TestDeadLetterHandler_resubmit(t *testing.T) {
+	fakeKibilog(t)
+	k := GetInstance()
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb4b")
+	k.AddLogPool(l)
+	t.Cleanup(func() { k.removeLogPool(l) })
+
+	k.SetDeadLetterHandler(func(letter DeadLetter) {
+		letter.Message.SetCreatedAt(k.GetClock().Now())
+		if errs := k.Resubmit([]DeadLetter{letter}); len(errs) != 0 {
+			t.Errorf("Resubmit() errors = %v", errs)
+		}
+	})
+	defer k.SetDeadLetterHandler(nil)
+
+	l.SetMessageTTL(time.Minute)
+	m, _ := NewMessage("expired", LevelInfo)
+	m.SetCreatedAt(time.Now().Add(-time.Hour))
+	l.AddMessage(m)
+
+	done := make(chan struct{})
+	go func() {
+		k.sendPool(context.Background(), l)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("sendPool() deadlocked on a handler that re-submits")
+	}
+	if l.Len() != 1 {
+		t.Errorf("Len() = %d, want the re-submitted message", l.Len())
+	}
+}
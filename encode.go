@@ -0,0 +1,309 @@
+package gokibilog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// maxEncodeDepth limits the nesting of params walked by [EncodeParams].
+const maxEncodeDepth = 64
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[reflect.Type]func(any) any{}
+	// interfaceEncoders lists the interface types of encoders in the order of registration.
+	interfaceEncoders []reflect.Type
+)
+
+// RegisterParamsEncoder registers a function that renders values of type T in params.
+// The result is sent as is, so it must be encodable by "encoding/json".
+// Registered encoders take precedence over the built-in rules of [EncodeParams].
+//
+// If T is an interface, for example error, the encoder renders values of every type implementing it.
+// An encoder of the exact type wins over them, otherwise the interface registered first wins.
+func RegisterParamsEncoder[T any](encode func(T) any) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	if _, ok := encoders[t]; !ok && t.Kind() == reflect.Interface {
+		interfaceEncoders = append(interfaceEncoders, t)
+	}
+	encoders[t] = func(v any) any {
+		return encode(v.(T))
+	}
+}
+
+func lookupEncoder(t reflect.Type) (func(any) any, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	if encode, ok := encoders[t]; ok {
+		return encode, true
+	}
+	for _, it := range interfaceEncoders {
+		if t.Implements(it) {
+			return encoders[it], true
+		}
+	}
+	return nil, false
+}
+
+// EncodeParams converts params into values that "encoding/json" renders meaningfully.
+// It is applied to the params of every message at send time.
+//
+// Anywhere inside maps, slices and structs:
+//
+// - values of types registered by [RegisterParamsEncoder] are rendered by their encoder
+//
+// - [slog.LogValuer] is resolved, slog groups become maps
+//
+// - [json.Marshaler] is kept as is
+//
+// - error is rendered as its text
+//
+// - [encoding.TextMarshaler] and [fmt.Stringer] are rendered as strings, for example time.Duration becomes "1.5s"
+//
+// Structs become maps of their exported fields, honouring the name, "-" and omitempty of json tags.
+func EncodeParams(params any) any {
+	var e paramsEncoder
+	return e.encode(reflect.ValueOf(params), "", 0)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// paramsEncoder walks params for [EncodeParams]. In the lenient mode it also replaces values
+// that "encoding/json" cannot encode and records what was replaced in issues.
+type paramsEncoder struct {
+	lenient  bool
+	issues   []string
+	visiting map[visit]bool
+}
+
+// visit identifies a pointer, map or slice on the current path, to detect cycles.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// child returns the path of a nested value, paths are only needed in the lenient mode.
+func (e *paramsEncoder) child(path string, key string, index bool) string {
+	if !e.lenient {
+		return ""
+	}
+	if index {
+		return path + "[" + key + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func (e *paramsEncoder) replace(path string, placeholder string, reason string) string {
+	if path == "" {
+		path = "params"
+	}
+	e.issues = append(e.issues, fmt.Sprintf("%s: %s", path, reason))
+	return placeholder
+}
+
+func (e *paramsEncoder) encode(v reflect.Value, path string, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxEncodeDepth {
+		if e.lenient {
+			return e.replace(path, "<max depth>", "nesting is deeper than the limit")
+		}
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	if v.Kind() == reflect.Interface {
+		return e.encode(v.Elem(), path, depth)
+	}
+	if e.lenient {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice:
+			key := visit{ptr: v.Pointer(), typ: v.Type()}
+			if v.Kind() == reflect.Slice {
+				key.len = v.Len()
+			}
+			if e.visiting[key] {
+				return e.replace(path, fmt.Sprintf("<cycle %s>", v.Type()), "cycle broken")
+			}
+			if e.visiting == nil {
+				e.visiting = map[visit]bool{}
+			}
+			e.visiting[key] = true
+			defer delete(e.visiting, key)
+		}
+	}
+
+	if v.CanInterface() {
+		if encode, ok := lookupEncoder(v.Type()); ok {
+			return encode(v.Interface())
+		}
+		switch i := v.Interface().(type) {
+		case slog.LogValuer:
+			return e.encodeSlogValue(i.LogValue().Resolve(), path, depth+1)
+		case slog.Value:
+			return e.encodeSlogValue(i.Resolve(), path, depth+1)
+		case json.Marshaler:
+			if e.lenient {
+				if _, err := json.Marshal(i); err != nil {
+					return e.replace(path, fmt.Sprintf("<%T>", i), fmt.Sprintf("MarshalJSON failed: %s", err.Error()))
+				}
+			}
+			return i
+		case error:
+			return i.Error()
+		case encoding.TextMarshaler:
+			text, err := i.MarshalText()
+			if err != nil {
+				return fmt.Sprintf("!ERROR: %s", err.Error())
+			}
+			return string(text)
+		case fmt.Stringer:
+			return i.String()
+		}
+		// Methods with pointer receivers of addressable values are not seen through the interface.
+		if v.Kind() != reflect.Pointer && v.CanAddr() {
+			if pt := v.Addr().Type(); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+				return e.encode(v.Addr(), path, depth)
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		return e.encode(v.Elem(), path, depth+1)
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := encodeMapKey(iter.Key())
+			m[key] = e.encode(iter.Value(), e.child(path, key, false), depth+1)
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 && v.Kind() == reflect.Slice {
+			return v.Interface()
+		}
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = e.encode(v.Index(i), e.child(path, fmt.Sprint(i), true), depth+1)
+		}
+		return s
+	case reflect.Struct:
+		m := map[string]any{}
+		e.encodeStruct(v, m, path, depth)
+		return m
+	}
+	if e.lenient {
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+				return e.replace(path, fmt.Sprint(f), "non-finite number replaced with a string")
+			}
+		case reflect.Complex64, reflect.Complex128:
+			return e.replace(path, fmt.Sprint(v.Complex()), "complex number replaced with a string")
+		case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+			return e.replace(path, fmt.Sprintf("<%s>", v.Type()), fmt.Sprintf("%s replaced with a placeholder", v.Kind()))
+		}
+	}
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return fmt.Sprint(v)
+}
+
+func encodeMapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if text, err := tm.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(k.Interface())
+}
+
+// encodeStruct puts the exported fields of the struct into m, embedded structs without a json name are flattened.
+func (e *paramsEncoder) encodeStruct(v reflect.Value, m map[string]any, path string, depth int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				ft, fv = ft.Elem(), fv.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				e.encodeStruct(fv, m, path, depth)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && fv.IsZero() {
+			continue
+		}
+		m[name] = e.encode(fv, e.child(path, name, false), depth+1)
+	}
+}
+
+func (e *paramsEncoder) encodeSlogValue(v slog.Value, path string, depth int) any {
+	switch v.Kind() {
+	case slog.KindGroup:
+		m := map[string]any{}
+		for _, attr := range v.Group() {
+			m[attr.Key] = e.encodeSlogValue(attr.Value.Resolve(), e.child(path, attr.Key, false), depth+1)
+		}
+		return m
+	case slog.KindDuration:
+		return v.Duration().String()
+	}
+	return e.encode(reflect.ValueOf(v.Any()), path, depth)
+}
+
+// withEncodedParams returns copies of the messages with params converted by [EncodeParams].
+func withEncodedParams(messages []*Message) []*Message {
+	encoded := make([]*Message, 0, len(messages))
+	for _, m := range messages {
+		if m == nil || m.Params == nil {
+			encoded = append(encoded, m)
+			continue
+		}
+		c := *m
+		c.Params = EncodeParams(m.Params)
+		encoded = append(encoded, &c)
+	}
+	return encoded
+}
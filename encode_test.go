@@ -0,0 +1,111 @@
+package gokibilog
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testUser struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Password string `json:"-"`
+	internal string
+	testAudit
+}
+
+type testAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type testSecret string
+
+func (s testSecret) LogValue() slog.Value {
+	return slog.StringValue("***")
+}
+
+type testMoney struct {
+	Amount   int64
+	Currency string
+}
+
+func (m testMoney) Cents() int64 {
+	return m.Amount
+}
+
+type testCents interface {
+	Cents() int64
+}
+
+type testFee int64
+
+func (f testFee) Cents() int64 {
+	return int64(f)
+}
+
+func TestEncodeParams(t *testing.T) {
+	RegisterParamsEncoder(func(m testMoney) any {
+		return map[string]any{"amount": float64(m.Amount) / 100, "currency": m.Currency}
+	})
+	RegisterParamsEncoder(func(c testCents) any {
+		return map[string]any{"amount": float64(c.Cents()) / 100}
+	})
+
+	createdAt := time.Date(2024, 01, 30, 15, 16, 59, 0, time.UTC)
+	tests := []struct {
+		name   string
+		params any
+		want   any
+	}{
+		{
+			name:   "nil",
+			params: nil,
+			want:   nil,
+		},
+		{
+			name:   "error and duration in map",
+			params: map[string]any{"error": errors.New("boom"), "took": 1500 * time.Millisecond},
+			want:   map[string]any{"error": "boom", "took": "1.5s"},
+		},
+		{
+			name:   "text marshaler in slice",
+			params: []any{net.ParseIP("192.0.2.1"), 1},
+			want:   []any{"192.0.2.1", 1},
+		},
+		{
+			name:   "json marshaler kept",
+			params: map[string]any{"createdAt": createdAt, "level": LevelWarning},
+			want:   map[string]any{"createdAt": createdAt, "level": LevelWarning},
+		},
+		{
+			name:   "struct with tags",
+			params: &testUser{Id: 1, Password: "secret", internal: "x", testAudit: testAudit{CreatedBy: "admin"}},
+			want:   map[string]any{"id": 1, "createdBy": "admin"},
+		},
+		{
+			name:   "slog values",
+			params: map[string]any{"token": testSecret("abc"), "group": slog.GroupValue(slog.Int("a", 1), slog.Duration("d", time.Second))},
+			want:   map[string]any{"token": "***", "group": map[string]any{"a": int64(1), "d": "1s"}},
+		},
+		{
+			name:   "registered encoder",
+			params: map[int]any{1: testMoney{Amount: 1050, Currency: "EUR"}},
+			want:   map[string]any{"1": map[string]any{"amount": 10.5, "currency": "EUR"}},
+		},
+		{
+			name:   "registered interface encoder",
+			params: map[string]any{"fee": testFee(250)},
+			want:   map[string]any{"fee": map[string]any{"amount": 2.5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodeParams(tt.params); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EncodeParams() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package gokibilog
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// ParamsProvider returns params that are added to every message at send time.
+// It is called once per sent [LogPool], so it should be fast.
+type ParamsProvider func() map[string]any
+
+// SetDefaultParams sets params that are added to messages of all [LogPool] at send time.
+//
+// Keys of the message win over the default params of [LogPool], which win over the default params of [Kibilog].
+// The params of the message itself are never modified. They are converted by [EncodeParams] before the default params
+// are added, so structs get them too. Params that are still not a map, for example a slice, are moved under
+// the "params" key next to the default params.
+//
+// The default params are not truncated by [Limits], which are applied on add. [Kibilog.SetMaxMessageBytes]
+// is checked again after they are added, larger messages are dropped like on add.
+func (k *Kibilog) SetDefaultParams(params map[string]any) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.defaultParams = copyParams(params)
+}
+
+// AddParamsProvider registers [ParamsProvider] whose params are added like [Kibilog.SetDefaultParams].
+// Providers are applied in the order of registration, the static default params win over them.
+func (k *Kibilog) AddParamsProvider(provider ParamsProvider) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.paramsProviders = append(k.paramsProviders, provider)
+}
+
+// SetDefaultParams sets params that are added to messages of [LogPool] at send time, see [Kibilog.SetDefaultParams].
+func (l *LogPool) SetDefaultParams(params map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.defaultParams = copyParams(params)
+}
+
+// defaultParamsFor returns poolParams, a copy of the default params of [LogPool] taken under its lock,
+// merged with the default params of [Kibilog].
+func (k *Kibilog) defaultParamsFor(poolParams map[string]any) map[string]any {
+	k.mu.Lock()
+	providers := k.paramsProviders
+	instanceParams := k.defaultParams
+	k.mu.Unlock()
+
+	params := map[string]any{}
+	for _, provider := range providers {
+		for key, v := range provider() {
+			params[key] = v
+		}
+	}
+	for key, v := range instanceParams {
+		params[key] = v
+	}
+	for key, v := range poolParams {
+		params[key] = v
+	}
+	return params
+}
+
+// withDefaultParams returns copies of the messages with the default params added to their params
+// converted by [EncodeParams].
+func withDefaultParams(messages []*Message, defaults map[string]any) []*Message {
+	if len(defaults) == 0 {
+		return messages
+	}
+	defaults, _ = EncodeParams(defaults).(map[string]any)
+	enriched := make([]*Message, 0, len(messages))
+	for _, m := range messages {
+		if m == nil {
+			enriched = append(enriched, m)
+			continue
+		}
+		c := *m
+		c.Params = addParams(m.Params, defaults)
+		enriched = append(enriched, &c)
+	}
+	return enriched
+}
+
+func copyParams(params map[string]any) map[string]any {
+	c := make(map[string]any, len(params))
+	for k, v := range params {
+		c[k] = v
+	}
+	return c
+}
+
+// HostnameParams is [ParamsProvider] of the "hostname" param.
+func HostnameParams() map[string]any {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil
+	}
+	return map[string]any{"hostname": hostname}
+}
+
+// ProcessParams is [ParamsProvider] of the "pid" param.
+func ProcessParams() map[string]any {
+	return map[string]any{"pid": os.Getpid()}
+}
+
+// GoVersionParams is [ParamsProvider] of the "goVersion" param.
+func GoVersionParams() map[string]any {
+	return map[string]any{"goVersion": runtime.Version()}
+}
+
+var buildParams = sync.OnceValue(func() map[string]any {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	params := map[string]any{
+		"module":        info.Main.Path,
+		"moduleVersion": info.Main.Version,
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			params["vcsRevision"] = setting.Value
+		case "vcs.time":
+			params["vcsTime"] = setting.Value
+		case "vcs.modified":
+			params["vcsModified"] = setting.Value == "true"
+		}
+	}
+	return params
+})
+
+// BuildInfoParams is [ParamsProvider] of the "module", "moduleVersion", "vcsRevision", "vcsTime" and "vcsModified"
+// params read from runtime/debug.ReadBuildInfo. Params missing in the build info are omitted.
+func BuildInfoParams() map[string]any {
+	return copyParams(buildParams())
+}
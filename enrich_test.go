@@ -0,0 +1,145 @@
+package gokibilog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestKibilog_SetDefaultParams(t *testing.T) {
+	received := fakeKibilog(t)
+	k := GetInstance()
+	k.SetDefaultParams(map[string]any{"service": "billing", "env": "prod"})
+	defer k.SetDefaultParams(nil)
+
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	l.SetDefaultParams(map[string]any{"env": "staging", "pool": "payments"})
+
+	params := map[string]any{"pool": "own"}
+	m1, _ := NewMessage("map params", LevelInfo)
+	m1.SetParams(params)
+	l.AddMessage(m1)
+	m2, _ := NewMessage("array params", LevelInfo)
+	m2.SetParams([]string{"sent"})
+	l.AddMessage(m2)
+	m3, _ := NewMessage("struct params", LevelInfo)
+	m3.SetParams(struct{ OrderID int }{OrderID: 1})
+	l.AddMessage(m3)
+
+	if errs := k.sendPool(context.Background(), l); len(errs) > 0 {
+		t.Fatalf("Errors: %v", errs)
+	}
+
+	got := received()
+	want := map[string]any{"service": "billing", "env": "staging", "pool": "own"}
+	if !reflect.DeepEqual(got[0].Params, want) {
+		t.Errorf("Params = %v, want %v", got[0].Params, want)
+	}
+	want = map[string]any{"service": "billing", "env": "staging", "pool": "payments", "params": []any{"sent"}}
+	if !reflect.DeepEqual(got[1].Params, want) {
+		t.Errorf("Array params = %v, want %v", got[1].Params, want)
+	}
+	want = map[string]any{"service": "billing", "env": "staging", "pool": "payments", "OrderID": float64(1)}
+	if !reflect.DeepEqual(got[2].Params, want) {
+		t.Errorf("Struct params = %v, want %v", got[2].Params, want)
+	}
+	if len(params) != 1 {
+		t.Errorf("Sending modified the params of the caller: %v", params)
+	}
+}
+
+func TestKibilog_SetDefaultParams_size(t *testing.T) {
+	received := fakeKibilog(t)
+	k := GetInstance()
+	queue := NewDeadLetterQueue(0)
+	k.SetDeadLetterHandler(queue.Handle)
+	defer k.SetDeadLetterHandler(nil)
+	k.SetDefaultParams(map[string]any{"padding": strings.Repeat("x", 100)})
+	defer k.SetDefaultParams(nil)
+	k.SetMaxMessageBytes(250)
+	defer k.SetMaxMessageBytes(0)
+
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	l.Info("enriched", "own", strings.Repeat("y", 50))
+	l.Info("fits")
+
+	errs := k.sendPool(context.Background(), l)
+	if len(errs) != 1 || !errors.Is(errs[0], ErrOversize) {
+		t.Errorf("sendPool() errors = %v, want one %v", errs, ErrOversize)
+	}
+	if got := received(); len(got) != 1 || got[0].Message != "fits" {
+		t.Errorf("Sent messages = %v, want only the one that fits", got)
+	}
+	if letters := queue.Letters(); len(letters) != 1 || letters[0].Reason != DeadLetterOversize || letters[0].Message.Message != "enriched" {
+		t.Errorf("Dead letters = %v, want the enriched message as oversize", letters)
+	}
+	if l.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", l.Len())
+	}
+}
+
+func TestLogPool_SetDefaultParams_whileSending(t *testing.T) {
+	fakeKibilog(t)
+	k := GetInstance()
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			l.SetDefaultParams(map[string]any{"i": i})
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		l.Info("test")
+		k.sendPool(context.Background(), l)
+	}
+	wg.Wait()
+}
+
+func TestParamsProviders(t *testing.T) {
+	hostname, _ := os.Hostname()
+	tests := []struct {
+		name     string
+		provider ParamsProvider
+		key      string
+		want     any
+	}{
+		{name: "hostname", provider: HostnameParams, key: "hostname", want: hostname},
+		{name: "pid", provider: ProcessParams, key: "pid", want: os.Getpid()},
+		{name: "go version", provider: GoVersionParams, key: "goVersion", want: runtime.Version()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.provider()[tt.key]; got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("build info", func(t *testing.T) {
+		if _, ok := BuildInfoParams()["module"]; !ok {
+			t.Errorf("BuildInfoParams() = %v, want module", BuildInfoParams())
+		}
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		k := GetInstance()
+		k.AddParamsProvider(ProcessParams)
+		defer func() {
+			k.mu.Lock()
+			k.paramsProviders = nil
+			k.mu.Unlock()
+		}()
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+		if got := k.defaultParamsFor(l.defaultParams)["pid"]; got != os.Getpid() {
+			t.Errorf("pid = %v, want %v", got, os.Getpid())
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package gokibilog
+
+import (
+	"log"
+)
+
+// ErrorHandler receives problems that cannot be returned to the caller,
+// for example an empty text passed to [LogPool.Info].
+type ErrorHandler func(err error)
+
+func defaultErrorHandler(err error) {
+	log.Printf("gokibilog: %s", err.Error())
+}
+
+// SetErrorHandler sets the default [ErrorHandler] for all [LogPool] that have not set their own.
+// Passing nil restores the default handler, which writes errors with the standard "log" package.
+func (k *Kibilog) SetErrorHandler(handler ErrorHandler) {
+	if handler == nil {
+		k.errorHandler.Store(nil)
+		return
+	}
+	k.errorHandler.Store(&handler)
+}
+
+// SetErrorHandler sets [ErrorHandler] of [LogPool]. Passing nil makes it use the default of [Kibilog].
+func (l *LogPool) SetErrorHandler(handler ErrorHandler) {
+	if handler == nil {
+		l.errorHandler.Store(nil)
+		return
+	}
+	l.errorHandler.Store(&handler)
+}
+
+func (l *LogPool) handleError(err error) {
+	if handler := l.errorHandler.Load(); handler != nil {
+		(*handler)(err)
+		return
+	}
+	GetInstance().handleError(err)
+}
+
+// handleError passes the error to the default [ErrorHandler] of [Kibilog].
+func (k *Kibilog) handleError(err error) {
+	if handler := k.errorHandler.Load(); handler != nil {
+		(*handler)(err)
+		return
+	}
+	defaultErrorHandler(err)
+}
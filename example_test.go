@@ -215,3 +215,32 @@ func Example_params() {
 		kibilog.SendMessages()
 	})
 }
+
+func Example_middleware() {
+	// The request logging from Example_params is available as a middleware.
+	kibilog := gokibilog.GetInstance()
+	kibilog.SetAuthToken("01htapnjvw83bz7xjhgcdwtry4")
+
+	logPool, err := gokibilog.NewLogPool("01htapms8kf6wyngde3mvyjn8x")
+	if err != nil {
+		log.Fatalf("Error creating logpool: %v", err)
+		return
+	}
+	kibilog.AddLogPool(logPool)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/user/create", func(w http.ResponseWriter, r *http.Request) {
+		// The middleware has already put the partition of the request into the context,
+		// so our own messages are grouped with the request message.
+		logPool.InfoContext(r.Context(), "Creating user")
+		fmt.Fprint(w, `{"done":1}`)
+	})
+
+	// Every request gets its own partition, and its method, path, status, duration and bodies are logged.
+	handler := gokibilog.Middleware(logPool, gokibilog.MiddlewareOptions{
+		RequestBodyLimit:  4096,
+		ResponseBodyLimit: 4096,
+	})(mux)
+
+	http.ListenAndServe(":8080", handler)
+}
@@ -0,0 +1,184 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// FlattenArrays defines how [FlattenParams] handles arrays.
+type FlattenArrays int
+
+const (
+	// FlattenArraysIndexed turns elements into keys with the index, for example "tags.0".
+	FlattenArraysIndexed FlattenArrays = iota
+	// FlattenArraysJSON turns arrays into JSON strings.
+	FlattenArraysJSON
+	// FlattenArraysKeepScalar keeps arrays of scalar values as arrays, other arrays are indexed.
+	FlattenArraysKeepScalar
+)
+
+// FlattenCollisions defines what [FlattenParams] does when two values get the same key,
+// for example the key "user.id" and the key "id" nested in "user".
+type FlattenCollisions int
+
+const (
+	// FlattenCollisionsSuffix keeps both values, the later one gets a suffix "_2", "_3" and so on.
+	FlattenCollisionsSuffix FlattenCollisions = iota
+	// FlattenCollisionsFirst keeps the first value.
+	FlattenCollisionsFirst
+	// FlattenCollisionsLast keeps the last value.
+	FlattenCollisionsLast
+)
+
+// DefaultFlattenMaxDepth is the nesting depth used by [FlattenParams] when FlattenOptions.MaxDepth is zero.
+const DefaultFlattenMaxDepth = 10
+
+// FlattenOptions configures [FlattenParams].
+type FlattenOptions struct {
+	// Separator joins the keys of the path. "." if empty.
+	Separator string
+	// MaxDepth is the maximum number of keys in a path, deeper values are turned into JSON strings.
+	// [DefaultFlattenMaxDepth] if zero.
+	MaxDepth   int
+	Arrays     FlattenArrays
+	Collisions FlattenCollisions
+}
+
+// SetFlattenParams enables flattening of the params of every message at send time with [FlattenParams].
+// Passing nil disables it, which is the default.
+func (k *Kibilog) SetFlattenParams(opts *FlattenOptions) {
+	if opts == nil {
+		k.flatten.Store(nil)
+		return
+	}
+	c := *opts
+	k.flatten.Store(&c)
+}
+
+// FlattenParams turns nested maps, structs and slices into a single map of dotted key paths with scalar values,
+// for example {"user": {"address": {"city": "Paris"}}} becomes {"user.address.city": "Paris"}.
+// The params are converted by [EncodeParams] first. Keys are processed in sorted order, so the result is deterministic.
+func FlattenParams(params any, opts FlattenOptions) map[string]any {
+	return flattenEncoded(EncodeParams(params), opts)
+}
+
+// flattenEncoded flattens params already converted by [EncodeParams] like [FlattenParams].
+func flattenEncoded(params any, opts FlattenOptions) map[string]any {
+	if opts.Separator == "" {
+		opts.Separator = "."
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultFlattenMaxDepth
+	}
+	f := flattener{opts: opts, result: map[string]any{}}
+	f.flatten("", params, 0)
+	return f.result
+}
+
+type flattener struct {
+	opts   FlattenOptions
+	result map[string]any
+}
+
+func (f *flattener) key(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + f.opts.Separator + key
+}
+
+func (f *flattener) flatten(prefix string, v any, depth int) {
+	switch value := v.(type) {
+	case map[string]any:
+		if len(value) == 0 || depth >= f.opts.MaxDepth {
+			f.setJSON(prefix, value)
+			return
+		}
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			f.flatten(f.key(prefix, k), value[k], depth+1)
+		}
+	case []any:
+		if len(value) == 0 || depth >= f.opts.MaxDepth || f.opts.Arrays == FlattenArraysJSON {
+			f.setJSON(prefix, value)
+			return
+		}
+		if f.opts.Arrays == FlattenArraysKeepScalar && allScalar(value) {
+			f.set(prefix, value)
+			return
+		}
+		for i, element := range value {
+			f.flatten(f.key(prefix, strconv.Itoa(i)), element, depth+1)
+		}
+	default:
+		f.set(prefix, value)
+	}
+}
+
+// setJSON sets the value encoded as a JSON string.
+func (f *flattener) setJSON(key string, v any) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		f.set(key, fmt.Sprint(v))
+		return
+	}
+	f.set(key, string(encoded))
+}
+
+func (f *flattener) set(key string, v any) {
+	if key == "" {
+		key = "value"
+	}
+	if _, exists := f.result[key]; exists {
+		switch f.opts.Collisions {
+		case FlattenCollisionsFirst:
+			return
+		case FlattenCollisionsSuffix:
+			for i := 2; ; i++ {
+				suffixed := key + "_" + strconv.Itoa(i)
+				if _, exists := f.result[suffixed]; !exists {
+					key = suffixed
+					break
+				}
+			}
+		}
+	}
+	f.result[key] = v
+}
+
+func allScalar(values []any) bool {
+	for _, v := range values {
+		switch v.(type) {
+		case map[string]any, []any:
+			return false
+		}
+		if v != nil {
+			if k := reflect.TypeOf(v).Kind(); k == reflect.Map || k == reflect.Slice || k == reflect.Array {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// withFlattenedParams returns copies of the messages with flattened params, which are already converted by [EncodeParams].
+func withFlattenedParams(messages []*Message, opts FlattenOptions) []*Message {
+	flattened := make([]*Message, 0, len(messages))
+	for _, m := range messages {
+		if m == nil || m.Params == nil {
+			flattened = append(flattened, m)
+			continue
+		}
+		c := *m
+		c.Params = flattenEncoded(m.Params, opts)
+		flattened = append(flattened, &c)
+	}
+	return flattened
+}
@@ -0,0 +1,108 @@
+package gokibilog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFlattenParams(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type user struct {
+		Id      int     `json:"id"`
+		Address address `json:"address"`
+	}
+
+	tests := []struct {
+		name   string
+		params any
+		opts   FlattenOptions
+		want   map[string]any
+	}{
+		{
+			name:   "nested struct",
+			params: map[string]any{"user": user{Id: 1, Address: address{City: "Paris"}}},
+			want:   map[string]any{"user.id": 1, "user.address.city": "Paris"},
+		},
+		{
+			name:   "separator",
+			params: map[string]any{"user": map[string]any{"id": 1}},
+			opts:   FlattenOptions{Separator: "_"},
+			want:   map[string]any{"user_id": 1},
+		},
+		{
+			name:   "max depth",
+			params: map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}},
+			opts:   FlattenOptions{MaxDepth: 2},
+			want:   map[string]any{"a.b": `{"c":1}`},
+		},
+		{
+			name:   "arrays indexed",
+			params: map[string]any{"tags": []string{"a", "b"}, "empty": []int{}},
+			want:   map[string]any{"tags.0": "a", "tags.1": "b", "empty": "[]"},
+		},
+		{
+			name:   "arrays json",
+			params: map[string]any{"tags": []string{"a", "b"}},
+			opts:   FlattenOptions{Arrays: FlattenArraysJSON},
+			want:   map[string]any{"tags": `["a","b"]`},
+		},
+		{
+			name:   "arrays keep scalar",
+			params: map[string]any{"tags": []string{"a"}, "items": []any{map[string]any{"id": 1}}},
+			opts:   FlattenOptions{Arrays: FlattenArraysKeepScalar},
+			want:   map[string]any{"tags": []any{"a"}, "items.0.id": 1},
+		},
+		{
+			name:   "collision suffix",
+			params: map[string]any{"user.id": 2, "user": map[string]any{"id": 1}},
+			want:   map[string]any{"user.id": 1, "user.id_2": 2},
+		},
+		{
+			name:   "collision first",
+			params: map[string]any{"user.id": 2, "user": map[string]any{"id": 1}},
+			opts:   FlattenOptions{Collisions: FlattenCollisionsFirst},
+			want:   map[string]any{"user.id": 1},
+		},
+		{
+			name:   "collision last",
+			params: map[string]any{"user.id": 2, "user": map[string]any{"id": 1}},
+			opts:   FlattenOptions{Collisions: FlattenCollisionsLast},
+			want:   map[string]any{"user.id": 2},
+		},
+		{
+			name:   "top level array",
+			params: []string{"sent", "delivered"},
+			want:   map[string]any{"0": "sent", "1": "delivered"},
+		},
+		{
+			name:   "scalar",
+			params: "sent",
+			want:   map[string]any{"value": "sent"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FlattenParams(tt.params, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlattenParams() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKibilog_SetFlattenParams(t *testing.T) {
+	received := fakeKibilog(t)
+	k := GetInstance()
+	k.SetFlattenParams(&FlattenOptions{})
+	defer k.SetFlattenParams(nil)
+
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	l.Info("test", "user", map[string]any{"id": 1})
+	k.sendPool(context.Background(), l)
+
+	if got := received(); len(got) != 1 || !reflect.DeepEqual(got[0].Params, map[string]any{"user.id": float64(1)}) {
+		t.Errorf("Received messages = %+v", got)
+	}
+}
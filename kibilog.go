@@ -1,8 +1,13 @@
 package gokibilog
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var once sync.Once
@@ -10,8 +15,21 @@ var instance *Kibilog
 
 // Kibilog is singleton entity. Use [GetInstance] to get this.
 type Kibilog struct {
-	mu    sync.Mutex
-	pools map[string]*LogPool
+	mu        sync.Mutex
+	pools     map[LogID]*LogPool
+	precision TimePrecision
+	clock     Clock
+	// noAutoCreatedAt is inverted, so that stamping is enabled by default.
+	noAutoCreatedAt bool
+	minLevel        atomic.Int64
+	defaultParams   map[string]any
+	paramsProviders []ParamsProvider
+	lenientParams   atomic.Bool
+	deadLetters     atomic.Pointer[DeadLetterHandler]
+	maxMessageBytes atomic.Int64
+	flatten         atomic.Pointer[FlattenOptions]
+	limits          atomic.Pointer[Limits]
+	errorHandler    atomic.Pointer[ErrorHandler]
 }
 
 // SetAuthToken registers the user's api token required to send messages to Kibilog.com
@@ -19,49 +37,205 @@ func (k *Kibilog) SetAuthToken(authToken string) {
 	getClientInstance().SetToken(authToken)
 }
 
+// SetClockSkewCorrection enables or disables shifting [Message.CreatedAt] by the measured clock skew before upload.
+//
+// The skew is measured by the Date header of Kibilog.com responses, so the correction starts working after the first send.
+// Messages in [LogPool] are not modified, only the uploaded copies.
+func (k *Kibilog) SetClockSkewCorrection(enabled bool) {
+	getClientInstance().skew.setCorrection(enabled)
+}
+
+// GetClockSkew returns the measured offset between the clock of Kibilog.com and the local [Clock].
+// A positive value means that the local clock is behind.
+//
+// ok is false until at least one response with a valid Date header has been received.
+func (k *Kibilog) GetClockSkew() (skew time.Duration, ok bool) {
+	return getClientInstance().skew.get()
+}
+
+// SetTimePrecision sets the unit in which [Message.SetCreatedAt] stores the time.
+//
+// By default, [PrecisionSecond] is used. Make sure that your log in Kibilog.com accepts the selected precision.
+func (k *Kibilog) SetTimePrecision(precision TimePrecision) error {
+	if !precision.isValid() {
+		return fmt.Errorf("Unknown time precision %v", precision)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.precision = precision
+	return nil
+}
+
+// GetTimePrecision returns the unit in which [Message.SetCreatedAt] stores the time.
+func (k *Kibilog) GetTimePrecision() TimePrecision {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.precision
+}
+
+// SetClock replaces the [Clock] used to stamp messages. Passing nil restores the system clock.
+func (k *Kibilog) SetClock(clock Clock) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if clock == nil {
+		clock = systemClock{}
+	}
+	k.clock = clock
+}
+
+// GetClock returns the [Clock] used to stamp messages.
+func (k *Kibilog) GetClock() Clock {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.clock
+}
+
+// SetAutoCreatedAt enables or disables stamping [Message.CreatedAt] in [NewMessage].
+//
+// It is enabled by default. If it is disabled, Kibilog.com substitutes the time it received the request.
+func (k *Kibilog) SetAutoCreatedAt(enabled bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.noAutoCreatedAt = !enabled
+}
+
+// SetMaxMessageBytes sets the maximum size of an encoded message. Larger messages are dropped when sending
+// and passed to [DeadLetterHandler] with [DeadLetterOversize]. 0 means no limit, which is the default.
+func (k *Kibilog) SetMaxMessageBytes(n int) {
+	k.maxMessageBytes.Store(int64(n))
+}
+
+// SetMinLevel sets the default minimum level for all [LogPool] that have not set their own by [LogPool.SetMinLevel].
+//
+// By default, all messages are accepted.
+func (k *Kibilog) SetMinLevel(level MessageLevel) {
+	k.minLevel.Store(int64(level))
+}
+
+// GetMinLevel returns the default minimum level of [LogPool].
+func (k *Kibilog) GetMinLevel() MessageLevel {
+	return MessageLevel(k.minLevel.Load())
+}
+
 // AddLogPool allows you to register another [LogPool].
 func (k *Kibilog) AddLogPool(pool *LogPool) {
 	k.mu.Lock()
 	defer k.mu.Unlock()
-	k.pools[pool.getLogId()] = pool
+	k.pools[pool.GetLogID()] = pool
+}
+
+// removeLogPool unregisters [LogPool], tests use it to keep the singleton clean.
+func (k *Kibilog) removeLogPool(pool *LogPool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.pools[pool.GetLogID()] == pool {
+		delete(k.pools, pool.GetLogID())
+	}
 }
 
 // GetLogPoolById returns [LogPool] by its LogID if it was previously set.
+// The id is parsed by [ParseLogID].
 //
 // Otherwise, it returns an error.
 func (k *Kibilog) GetLogPoolById(logId string) (logPool *LogPool, err error) {
-	logPool, ok := k.pools[logId]
+	id, err := ParseLogID(strings.Trim(logId, " "))
+	if err != nil {
+		return nil, err
+	}
+	return k.getLogPool(id)
+}
+
+func (k *Kibilog) getLogPool(id LogID) (*LogPool, error) {
+	k.mu.Lock()
+	logPool, ok := k.pools[id]
+	k.mu.Unlock()
 	if !ok {
-		return nil, fmt.Errorf("LogPool with id \"%s\" is not found!", logId)
+		return nil, fmt.Errorf("LogPool with id \"%s\" is not found!", id)
 	}
 	return logPool, nil
 }
 
 // SendMessages sends all messages that were previously posted in all registered [LogPool].
 func (k *Kibilog) SendMessages() (errs []error) {
-	for _, pool := range k.pools {
-		if len(pool.messages) == 0 {
-			continue
+	return k.SendMessagesContext(context.Background())
+}
+
+// SendMessagesContext sends messages like [Kibilog.SendMessages], the requests are cancelled with ctx.
+func (k *Kibilog) SendMessagesContext(ctx context.Context) (errs []error) {
+	for _, pool := range k.logPools() {
+		errs = append(errs, k.sendPool(ctx, pool)...)
+	}
+	return errs
+}
+
+// sendPool takes the messages out of [LogPool] and sends them. The pool is not locked during the request,
+// so messages can be added meanwhile. If the sending fails, the messages are put back in front of them.
+func (k *Kibilog) sendPool(ctx context.Context, pool *LogPool) (errs []error) {
+	pool.mu.Lock()
+	letters := pool.removeExpiredMessages()
+	errs, invalid := validatePool(pool)
+	batch := pool.messages
+	pool.messages = []*Message{}
+	poolParams := copyParams(pool.defaultParams)
+	pool.mu.Unlock()
+
+	k.deadLetterAll(pool.logId, append(letters, invalid...))
+	if len(batch) == 0 {
+		return errs
+	}
+
+	dropped, err := getClientInstance().Send(ctx, pool, batch, k.defaultParamsFor(poolParams))
+	if len(dropped) > 0 {
+		removed := make(map[*Message]bool, len(dropped))
+		for _, d := range dropped {
+			droppedErr := fmt.Errorf("Error in the message for \"%s\": %w", pool.logId, d.err)
+			k.deadLetter(pool.logId, d.message, d.reason, droppedErr)
+			errs = append(errs, droppedErr)
+			removed[d.message] = true
 		}
-		poolErrs := validatePool(pool)
-		if len(poolErrs) > 0 {
-			errs = append(errs, poolErrs...)
+		batch = removeMessages(batch, removed)
+	}
+	if err == nil {
+		return errs
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Rejected() && k.hasDeadLetterHandler() {
+		for _, m := range batch {
+			k.deadLetter(pool.logId, m, DeadLetterRejected, err)
 		}
-		err := getClientInstance().Send(pool)
-		if err != nil {
-			errs = append(errs, err)
-		} else {
-			pool.messages = []*Message{}
+	} else {
+		pool.requeue(batch)
+	}
+	return append(errs, err)
+}
+
+// removeMessages returns the messages without the removed ones.
+func removeMessages(messages []*Message, removed map[*Message]bool) []*Message {
+	kept := make([]*Message, 0, len(messages))
+	for _, m := range messages {
+		if !removed[m] {
+			kept = append(kept, m)
 		}
 	}
-	return errs
+	return kept
+}
+
+// createdAt returns the current time of the clock if messages should be stamped.
+func (k *Kibilog) createdAt() (createdAt time.Time, ok bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.noAutoCreatedAt {
+		return time.Time{}, false
+	}
+	return k.clock.Now(), true
 }
 
 // GetInstance allows you to get a single instance of [Kibilog].
 func GetInstance() *Kibilog {
 	once.Do(func() {
 		instance = new(Kibilog)
-		instance.pools = make(map[string]*LogPool)
+		instance.pools = make(map[LogID]*LogPool)
+		instance.clock = systemClock{}
 	})
 	return instance
 }
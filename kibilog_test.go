@@ -1,8 +1,12 @@
 package gokibilog
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestGetInstance(t *testing.T) {
@@ -48,3 +52,49 @@ func TestKibilog_GetSetAuthToken(t *testing.T) {
 		})
 	}
 }
+
+func TestKibilog_sendPool_unlocked(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	c := getClientInstance()
+	baseUrl := c.baseUrl
+	c.baseUrl = srv.URL
+	t.Cleanup(func() {
+		c.baseUrl = baseUrl
+		srv.Close()
+	})
+
+	k := GetInstance()
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	l.Info("first")
+
+	sent := make(chan []error)
+	go func() {
+		sent <- k.sendPool(context.Background(), l)
+	}()
+	for l.Len() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	added := make(chan struct{})
+	go func() {
+		l.Info("second")
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Fatalf("Info() was blocked by the send in flight")
+	}
+
+	close(release)
+	if errs := <-sent; len(errs) != 1 {
+		t.Fatalf("sendPool() errors = %v, want 1", errs)
+	}
+	if l.Len() != 2 || l.messages[0].Message != "first" || l.messages[1].Message != "second" {
+		t.Errorf("The failed batch was not put back in front of the new messages")
+	}
+}
@@ -0,0 +1,127 @@
+package gokibilog
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPodInfoDir is the directory where the Downward API volume is expected to be mounted.
+const DefaultPodInfoDir = "/etc/podinfo"
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+var containerIdRegexp = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// KubernetesParams is [ParamsProvider] of the Kubernetes and container metadata,
+// using [DefaultPodInfoDir] for the Downward API volume. See [NewKubernetesParams].
+var KubernetesParams = NewKubernetesParams(DefaultPodInfoDir)
+
+// NewKubernetesParams returns [ParamsProvider] of the Kubernetes and container metadata.
+//
+// The "kubernetes" param contains podName, namespace, nodeName, podIp and containerName read from
+// the POD_NAME, POD_NAMESPACE, NODE_NAME, POD_IP and CONTAINER_NAME environment variables of the Downward API,
+// and labels and annotations read from the "labels" and "annotations" files in podInfoDir.
+// The "containerId" param is detected from /proc/self/cgroup and /proc/self/mountinfo.
+//
+// The metadata is read once and cached. Everything that is missing is omitted,
+// so outside a cluster or a container the provider returns nothing.
+func NewKubernetesParams(podInfoDir string) ParamsProvider {
+	load := sync.OnceValue(func() map[string]any {
+		return kubernetesParams("/", podInfoDir, os.Getenv)
+	})
+	return func() map[string]any {
+		return copyParams(load())
+	}
+}
+
+func kubernetesParams(root string, podInfoDir string, getenv func(string) string) map[string]any {
+	params := map[string]any{}
+	k8s := map[string]any{}
+
+	for key, env := range map[string]string{
+		"podName":       "POD_NAME",
+		"namespace":     "POD_NAMESPACE",
+		"nodeName":      "NODE_NAME",
+		"podIp":         "POD_IP",
+		"containerName": "CONTAINER_NAME",
+	} {
+		if v := getenv(env); v != "" {
+			k8s[key] = v
+		}
+	}
+
+	// Inside a cluster the hostname is the pod name and the namespace is mounted with the service account.
+	if getenv("KUBERNETES_SERVICE_HOST") != "" {
+		if _, ok := k8s["podName"]; !ok && getenv("HOSTNAME") != "" {
+			k8s["podName"] = getenv("HOSTNAME")
+		}
+		if _, ok := k8s["namespace"]; !ok {
+			if namespace, err := os.ReadFile(filepath.Join(root, serviceAccountNamespaceFile)); err == nil {
+				k8s["namespace"] = strings.TrimSpace(string(namespace))
+			}
+		}
+	}
+
+	if labels := readPodInfoFile(filepath.Join(root, podInfoDir, "labels")); len(labels) > 0 {
+		k8s["labels"] = labels
+	}
+	if annotations := readPodInfoFile(filepath.Join(root, podInfoDir, "annotations")); len(annotations) > 0 {
+		k8s["annotations"] = annotations
+	}
+
+	if len(k8s) > 0 {
+		params["kubernetes"] = k8s
+	}
+	if containerId := detectContainerId(root); containerId != "" {
+		params["containerId"] = containerId
+	}
+	return params
+}
+
+// readPodInfoFile parses a Downward API file with key="value" lines.
+func readPodInfoFile(path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values
+}
+
+// detectContainerId looks for a 64 hex digits container ID in the cgroups and mounts of the process.
+func detectContainerId(root string) string {
+	for _, file := range []string{"proc/self/cgroup", "proc/self/mountinfo"} {
+		content, err := os.ReadFile(filepath.Join(root, file))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			// With cgroup v2 the ID is only in the mounts of /etc/hostname and others from the containers directory.
+			if file == "proc/self/mountinfo" && !strings.Contains(line, "/containers/") {
+				continue
+			}
+			if id := containerIdRegexp.FindString(line); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
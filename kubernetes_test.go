@@ -0,0 +1,77 @@
+package gokibilog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_kubernetesParams(t *testing.T) {
+	containerId := "3f4ab2c1d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5"
+	tests := []struct {
+		name  string
+		env   map[string]string
+		files map[string]string
+		want  map[string]any
+	}{
+		{
+			name: "outside cluster",
+			want: map[string]any{},
+		},
+		{
+			name: "downward api",
+			env: map[string]string{
+				"POD_NAME":      "billing-7d9f",
+				"POD_NAMESPACE": "prod",
+				"NODE_NAME":     "node-1",
+			},
+			files: map[string]string{
+				"etc/podinfo/labels": "app=\"billing\"\ntier=\"backend\"\n",
+				"proc/self/cgroup":   "0::/kubepods/burstable/pod1/cri-containerd-" + containerId + ".scope\n",
+			},
+			want: map[string]any{
+				"kubernetes": map[string]any{
+					"podName":   "billing-7d9f",
+					"namespace": "prod",
+					"nodeName":  "node-1",
+					"labels":    map[string]string{"app": "billing", "tier": "backend"},
+				},
+				"containerId": containerId,
+			},
+		},
+		{
+			name: "service account fallback",
+			env: map[string]string{
+				"KUBERNETES_SERVICE_HOST": "10.0.0.1",
+				"HOSTNAME":                "billing-7d9f",
+			},
+			files: map[string]string{
+				"var/run/secrets/kubernetes.io/serviceaccount/namespace": "prod\n",
+				"proc/self/cgroup":    "0::/\n",
+				"proc/self/mountinfo": "1 2 0:1 /var/lib/docker/containers/" + containerId + "/hostname /etc/hostname rw\n",
+			},
+			want: map[string]any{
+				"kubernetes": map[string]any{
+					"podName":   "billing-7d9f",
+					"namespace": "prod",
+				},
+				"containerId": containerId,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, name)
+				os.MkdirAll(filepath.Dir(path), 0o755)
+				os.WriteFile(path, []byte(content), 0o644)
+			}
+			got := kubernetesParams(root, DefaultPodInfoDir, func(key string) string { return tt.env[key] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kubernetesParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var levelNames = map[MessageLevel]string{
+	LevelDebug:     "debug",
+	LevelInfo:      "info",
+	LevelNotice:    "notice",
+	LevelWarning:   "warning",
+	LevelError:     "error",
+	LevelCritical:  "critical",
+	LevelAlert:     "alert",
+	LevelEmergency: "emergency",
+}
+
+// levelAliases contains names and common abbreviations of levels accepted by [ParseLevel].
+var levelAliases = map[string]MessageLevel{
+	"debug":       LevelDebug,
+	"dbg":         LevelDebug,
+	"info":        LevelInfo,
+	"inf":         LevelInfo,
+	"information": LevelInfo,
+	"notice":      LevelNotice,
+	"note":        LevelNotice,
+	"warning":     LevelWarning,
+	"warn":        LevelWarning,
+	"wrn":         LevelWarning,
+	"error":       LevelError,
+	"err":         LevelError,
+	"critical":    LevelCritical,
+	"crit":        LevelCritical,
+	"alert":       LevelAlert,
+	"emergency":   LevelEmergency,
+	"emerg":       LevelEmergency,
+}
+
+// String returns the lowercase name of the level, for example "warning".
+// Unknown levels are returned as "MessageLevel(42)".
+func (l MessageLevel) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("MessageLevel(%d)", int(l))
+}
+
+func (l MessageLevel) isValid() bool {
+	_, ok := levelNames[l]
+	return ok
+}
+
+// ParseLevel converts a string to [MessageLevel].
+//
+// Names ("warning"), abbreviations ("WARN", "ERR") and numeric values ("40") are accepted, case-insensitively.
+func ParseLevel(level string) (MessageLevel, error) {
+	s := strings.ToLower(strings.Trim(level, " "))
+	if l, ok := levelAliases[s]; ok {
+		return l, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && MessageLevel(n).isValid() {
+		return MessageLevel(n), nil
+	}
+	return 0, fmt.Errorf("The \"%s\" is not a known message level", level)
+}
+
+// MarshalText implements [encoding.TextMarshaler] using the name of the level.
+func (l MessageLevel) MarshalText() ([]byte, error) {
+	if !l.isValid() {
+		return nil, fmt.Errorf("Unknown message level %d", int(l))
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler] using [ParseLevel].
+func (l *MessageLevel) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// MarshalJSON keeps the numeric wire format expected by Kibilog.com.
+func (l MessageLevel) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(l))), nil
+}
+
+// UnmarshalJSON accepts both numeric values and strings understood by [ParseLevel].
+// Like [ParseLevel], it rejects unknown levels.
+func (l *MessageLevel) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		if !MessageLevel(n).isValid() {
+			return fmt.Errorf("Unknown message level %d", n)
+		}
+		*l = MessageLevel(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("Message level must be a number or a string: %s", err.Error())
+	}
+	return l.UnmarshalText([]byte(s))
+}
+
+// Set implements [flag.Value] using [ParseLevel].
+func (l *MessageLevel) Set(value string) error {
+	return l.UnmarshalText([]byte(value))
+}
@@ -0,0 +1,118 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+)
+
+func TestMessageLevel_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		level MessageLevel
+		want  string
+	}{
+		{level: LevelDebug, want: "debug"},
+		{level: LevelWarning, want: "warning"},
+		{level: LevelEmergency, want: "emergency"},
+		{level: MessageLevel(42), want: "MessageLevel(42)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.level.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		want    MessageLevel
+		wantErr bool
+	}{
+		{level: "warning", want: LevelWarning},
+		{level: "WARN", want: LevelWarning},
+		{level: " Err ", want: LevelError},
+		{level: "crit", want: LevelCritical},
+		{level: "40", want: LevelWarning},
+		{level: "42", wantErr: true},
+		{level: "verbose", wantErr: true},
+		{level: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			got, err := ParseLevel(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageLevel_MarshalText(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		text, err := LevelNotice.MarshalText()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		var l MessageLevel
+		if err := l.UnmarshalText(text); err != nil || l != LevelNotice {
+			t.Errorf("UnmarshalText(%s) = %v, %v, want %v", text, l, err, LevelNotice)
+		}
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		if _, err := MessageLevel(42).MarshalText(); err == nil {
+			t.Errorf("An unknown level was marshalled and no error was caused")
+		}
+	})
+}
+
+func TestMessageLevel_JSON(t *testing.T) {
+	t.Run("numeric wire format", func(t *testing.T) {
+		m, _ := NewMessage("test", LevelWarning)
+		body, _ := json.Marshal(m)
+		var raw map[string]any
+		json.Unmarshal(body, &raw)
+		if raw["level"] != float64(40) {
+			t.Errorf("level = %#v, want 40", raw["level"])
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var levels []MessageLevel
+		if err := json.Unmarshal([]byte(`[50, "warn"]`), &levels); err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		if levels[0] != LevelError || levels[1] != LevelWarning {
+			t.Errorf("UnmarshalJSON() = %v, want [error warning]", levels)
+		}
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		for _, data := range []string{`42`, `9999`, `"9999"`} {
+			level := LevelInfo
+			if err := json.Unmarshal([]byte(data), &level); err == nil {
+				t.Errorf("UnmarshalJSON(%s) = %v, want an error", data, level)
+			}
+		}
+	})
+}
+
+func TestMessageLevel_Set(t *testing.T) {
+	level := LevelInfo
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&level, "level", "message level")
+	if err := fs.Parse([]string{"-level", "ERR"}); err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if level != LevelError {
+		t.Errorf("Set() = %v, want %v", level, LevelError)
+	}
+}
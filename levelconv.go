@@ -0,0 +1,149 @@
+package gokibilog
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// levelsAscending contains the known levels from the least to the most severe.
+var levelsAscending = []MessageLevel{
+	LevelDebug, LevelInfo, LevelNotice, LevelWarning, LevelError, LevelCritical, LevelAlert, LevelEmergency,
+}
+
+// Round returns the nearest known level that is not more severe than l.
+// Values below [LevelDebug] become [LevelDebug], values above [LevelEmergency] become [LevelEmergency].
+func (l MessageLevel) Round() MessageLevel {
+	rounded := LevelDebug
+	for _, known := range levelsAscending {
+		if l >= known {
+			rounded = known
+		}
+	}
+	return rounded
+}
+
+// LevelFromSyslog converts the RFC 5424 severity (0 - Emergency ... 7 - Debug) to [MessageLevel].
+func LevelFromSyslog(severity int) (MessageLevel, error) {
+	if severity < 0 || severity > 7 {
+		return 0, fmt.Errorf("The syslog severity must be between 0 and 7, passed %d", severity)
+	}
+	return levelsAscending[7-severity], nil
+}
+
+// Syslog returns the RFC 5424 severity of the level. In-between values are rounded by [MessageLevel.Round].
+func (l MessageLevel) Syslog() int {
+	rounded := l.Round()
+	for i, known := range levelsAscending {
+		if known == rounded {
+			return 7 - i
+		}
+	}
+	return 7
+}
+
+// slogLevels contains the [slog.Level] of each known level. slog has no Notice, Critical, Alert and Emergency,
+// so they are placed between and above the standard slog levels with the same step of 4.
+var slogLevels = map[MessageLevel]slog.Level{
+	LevelDebug:     slog.LevelDebug,
+	LevelInfo:      slog.LevelInfo,
+	LevelNotice:    slog.LevelInfo + 2,
+	LevelWarning:   slog.LevelWarn,
+	LevelError:     slog.LevelError,
+	LevelCritical:  slog.LevelError + 4,
+	LevelAlert:     slog.LevelError + 8,
+	LevelEmergency: slog.LevelError + 12,
+}
+
+// LevelFromSlog converts [slog.Level] to [MessageLevel].
+//
+// Custom slog levels are rounded down to the nearest level, for example slog.LevelWarn+2 becomes [LevelWarning],
+// and everything below slog.LevelInfo becomes [LevelDebug].
+func LevelFromSlog(level slog.Level) MessageLevel {
+	converted := LevelDebug
+	for _, known := range levelsAscending {
+		if level >= slogLevels[known] {
+			converted = known
+		}
+	}
+	return converted
+}
+
+// Slog returns the [slog.Level] of the level. In-between values are rounded by [MessageLevel.Round].
+func (l MessageLevel) Slog() slog.Level {
+	return slogLevels[l.Round()]
+}
+
+var logrusLevels = map[string]MessageLevel{
+	"trace":   LevelDebug,
+	"debug":   LevelDebug,
+	"info":    LevelInfo,
+	"warn":    LevelWarning,
+	"warning": LevelWarning,
+	"error":   LevelError,
+	"fatal":   LevelCritical,
+	"panic":   LevelEmergency,
+}
+
+// LevelFromLogrus converts the name of a logrus level to [MessageLevel].
+func LevelFromLogrus(name string) (MessageLevel, error) {
+	if l, ok := logrusLevels[strings.ToLower(strings.Trim(name, " "))]; ok {
+		return l, nil
+	}
+	return 0, fmt.Errorf("The \"%s\" is not a logrus level", name)
+}
+
+// Logrus returns the name of the logrus level. Levels without a logrus equivalent are rounded down,
+// [LevelNotice] becomes "info" and [LevelAlert] becomes "fatal".
+func (l MessageLevel) Logrus() string {
+	switch l.Round() {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo, LevelNotice:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	case LevelCritical, LevelAlert:
+		return "fatal"
+	}
+	return "panic"
+}
+
+var zapLevels = map[string]MessageLevel{
+	"debug":  LevelDebug,
+	"info":   LevelInfo,
+	"warn":   LevelWarning,
+	"error":  LevelError,
+	"dpanic": LevelCritical,
+	"panic":  LevelAlert,
+	"fatal":  LevelEmergency,
+}
+
+// LevelFromZap converts the name of a zap level to [MessageLevel].
+func LevelFromZap(name string) (MessageLevel, error) {
+	if l, ok := zapLevels[strings.ToLower(strings.Trim(name, " "))]; ok {
+		return l, nil
+	}
+	return 0, fmt.Errorf("The \"%s\" is not a zap level", name)
+}
+
+// Zap returns the name of the zap level. [LevelNotice] has no zap equivalent and becomes "info".
+func (l MessageLevel) Zap() string {
+	switch l.Round() {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo, LevelNotice:
+		return "info"
+	case LevelWarning:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelCritical:
+		return "dpanic"
+	case LevelAlert:
+		return "panic"
+	}
+	return "fatal"
+}
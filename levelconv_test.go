@@ -0,0 +1,101 @@
+package gokibilog
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestMessageLevel_Round(t *testing.T) {
+	tests := []struct {
+		level MessageLevel
+		want  MessageLevel
+	}{
+		{level: 0, want: LevelDebug},
+		{level: LevelDebug, want: LevelDebug},
+		{level: 45, want: LevelWarning},
+		{level: LevelEmergency, want: LevelEmergency},
+		{level: 100, want: LevelEmergency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			if got := tt.level.Round(); got != tt.want {
+				t.Errorf("Round(%d) = %v, want %v", int(tt.level), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelFromSyslog(t *testing.T) {
+	for severity := 0; severity <= 7; severity++ {
+		l, err := LevelFromSyslog(severity)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		if got := l.Syslog(); got != severity {
+			t.Errorf("LevelFromSyslog(%d).Syslog() = %v", severity, got)
+		}
+	}
+	if l, _ := LevelFromSyslog(4); l != LevelWarning {
+		t.Errorf("LevelFromSyslog(4) = %v, want %v", l, LevelWarning)
+	}
+	if _, err := LevelFromSyslog(8); err == nil {
+		t.Errorf("An invalid severity was passed and no error was caused")
+	}
+}
+
+func TestLevelFromSlog(t *testing.T) {
+	tests := []struct {
+		name  string
+		level slog.Level
+		want  MessageLevel
+	}{
+		{name: "below debug", level: slog.LevelDebug - 4, want: LevelDebug},
+		{name: "debug", level: slog.LevelDebug, want: LevelDebug},
+		{name: "info", level: slog.LevelInfo, want: LevelInfo},
+		{name: "between info and warn", level: slog.LevelInfo + 2, want: LevelNotice},
+		{name: "warn", level: slog.LevelWarn, want: LevelWarning},
+		{name: "custom above warn", level: slog.LevelWarn + 2, want: LevelWarning},
+		{name: "error", level: slog.LevelError, want: LevelError},
+		{name: "far above error", level: slog.LevelError + 100, want: LevelEmergency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LevelFromSlog(tt.level); got != tt.want {
+				t.Errorf("LevelFromSlog() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, l := range levelsAscending {
+		if got := LevelFromSlog(l.Slog()); got != l {
+			t.Errorf("LevelFromSlog(%v.Slog()) = %v", l, got)
+		}
+	}
+}
+
+func TestLevelFromLogrusZap(t *testing.T) {
+	for _, name := range []string{"debug", "info", "warning", "error", "fatal", "panic"} {
+		l, err := LevelFromLogrus(name)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		if got := l.Logrus(); got != name {
+			t.Errorf("LevelFromLogrus(%s).Logrus() = %v", name, got)
+		}
+	}
+	for _, name := range []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"} {
+		l, err := LevelFromZap(name)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		if got := l.Zap(); got != name {
+			t.Errorf("LevelFromZap(%s).Zap() = %v", name, got)
+		}
+	}
+	if _, err := LevelFromLogrus("dpanic"); err == nil {
+		t.Errorf("An unknown logrus level was passed and no error was caused")
+	}
+	if got := LevelNotice.Zap(); got != "info" {
+		t.Errorf("LevelNotice.Zap() = %v, want info", got)
+	}
+}
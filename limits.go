@@ -0,0 +1,194 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// TruncatedKey is the reserved params key set when the params were cut by [Limits].MaxParamsBytes.
+const TruncatedKey = "_truncated"
+
+// Limits bounds the size of messages. They are applied by [LogPool.AddMessage],
+// values over the limits are truncated with a marker instead of rejecting the message. Zero fields mean no limit.
+// The markers count towards the limits, so truncated values never exceed them.
+type Limits struct {
+	// MaxMessageRunes limits the length of the message text.
+	MaxMessageRunes int
+	// MaxStringRunes limits the length of every string value in params.
+	MaxStringRunes int
+	// MaxCollectionLen limits the number of elements of every map, struct and slice in params.
+	MaxCollectionLen int
+	// MaxParamsBytes limits the size of the JSON encoded params. Larger params are replaced
+	// with {"_truncated": true, "preview": "<the beginning of the JSON>"}, or with {"_truncated": true}
+	// if the limit is too small for a preview, or with nil if it is too small even for that.
+	MaxParamsBytes int
+}
+
+func (l Limits) limitsParams() bool {
+	return l.MaxStringRunes > 0 || l.MaxCollectionLen > 0 || l.MaxParamsBytes > 0
+}
+
+// SetLimits sets the default [Limits] for all [LogPool] that have not set their own.
+func (k *Kibilog) SetLimits(limits Limits) {
+	k.limits.Store(&limits)
+}
+
+// SetLimits sets [Limits] of [LogPool]. Passing nil makes it use the default of [Kibilog].
+func (l *LogPool) SetLimits(limits *Limits) {
+	if limits == nil {
+		l.limits.Store(nil)
+		return
+	}
+	c := *limits
+	l.limits.Store(&c)
+}
+
+func (l *LogPool) getLimits() Limits {
+	if limits := l.limits.Load(); limits != nil {
+		return *limits
+	}
+	if limits := GetInstance().limits.Load(); limits != nil {
+		return *limits
+	}
+	return Limits{}
+}
+
+// applyLimits truncates the text and params of the message.
+func (l *LogPool) applyLimits(m *Message) {
+	limits := l.getLimits()
+	if limits.MaxMessageRunes > 0 {
+		m.Message = truncateString(m.Message, limits.MaxMessageRunes)
+	}
+	if m.Params == nil || !limits.limitsParams() {
+		return
+	}
+	params := truncateValue(EncodeParams(m.Params), limits)
+	if limits.MaxParamsBytes > 0 {
+		if encoded, err := json.Marshal(params); err == nil && len(encoded) > limits.MaxParamsBytes {
+			params = truncatedParams(string(encoded), limits.MaxParamsBytes)
+		}
+	}
+	m.Params = params
+}
+
+// truncatedParams returns the replacement of params whose JSON is too large, encoded within max bytes.
+func truncatedParams(encoded string, max int) any {
+	// The preview is escaped once more inside the wrapper, so it is shortened until the whole wrapper fits.
+	budget := max
+	for budget > 0 {
+		params := map[string]any{
+			TruncatedKey: true,
+			"preview":    truncateBytes(encoded, budget),
+		}
+		wrapped, err := json.Marshal(params)
+		if err != nil {
+			break
+		}
+		if len(wrapped) <= max {
+			return params
+		}
+		budget -= len(wrapped) - max
+	}
+	params := map[string]any{TruncatedKey: true}
+	if wrapped, err := json.Marshal(params); err == nil && len(wrapped) <= max {
+		return params
+	}
+	return nil
+}
+
+// truncateString cuts the string to at most max runes including a marker with the number of cut runes.
+// If max is too small for the marker, the string is cut without it.
+func truncateString(s string, max int) string {
+	n := utf8.RuneCountInString(s)
+	if n <= max {
+		return s
+	}
+	marker := func(keep int) string {
+		return fmt.Sprintf("…[truncated %d runes]", n-keep)
+	}
+	keep := max - utf8.RuneCountInString(marker(max))
+	for keep > 0 && keep+utf8.RuneCountInString(marker(keep)) > max {
+		keep--
+	}
+	if keep <= 0 {
+		return runePrefix(s, max)
+	}
+	return runePrefix(s, keep) + marker(keep)
+}
+
+// runePrefix returns the first n runes of the string.
+func runePrefix(s string, n int) string {
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
+
+// truncateBytes cuts the string to at most max bytes without breaking UTF-8 sequences.
+func truncateBytes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// truncateValue applies the string and collection limits to params converted by [EncodeParams].
+func truncateValue(v any, limits Limits) any {
+	switch value := v.(type) {
+	case string:
+		if limits.MaxStringRunes > 0 {
+			return truncateString(value, limits.MaxStringRunes)
+		}
+		return value
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		cut := 0
+		if limits.MaxCollectionLen > 0 && len(keys) > limits.MaxCollectionLen {
+			keep := collectionKeep(limits.MaxCollectionLen)
+			cut = len(keys) - keep
+			keys = keys[:keep]
+		}
+		m := make(map[string]any, len(keys)+1)
+		for _, k := range keys {
+			m[k] = truncateValue(value[k], limits)
+		}
+		if cut > 0 {
+			m["…"] = fmt.Sprintf("[truncated %d keys]", cut)
+		}
+		return m
+	case []any:
+		cut := 0
+		if limits.MaxCollectionLen > 0 && len(value) > limits.MaxCollectionLen {
+			keep := collectionKeep(limits.MaxCollectionLen)
+			cut = len(value) - keep
+			value = value[:keep]
+		}
+		s := make([]any, 0, len(value)+1)
+		for _, element := range value {
+			s = append(s, truncateValue(element, limits))
+		}
+		if cut > 0 {
+			s = append(s, fmt.Sprintf("…[truncated %d elements]", cut))
+		}
+		return s
+	}
+	return v
+}
+
+// collectionKeep returns how many elements of a truncated collection are kept, leaving room for the marker.
+func collectionKeep(max int) int {
+	return max - 1
+}
@@ -0,0 +1,124 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func Test_truncateString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{name: "short", s: "test", max: 10, want: "test"},
+		{name: "ascii", s: strings.Repeat("response body ", 5), max: 30, want: "response …[truncated 61 runes]"},
+		{name: "runes", s: strings.Repeat("привет мир ", 4), max: 30, want: "привет ми…[truncated 35 runes]"},
+		{name: "no room for the marker", s: "response body", max: 8, want: "response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateString(tt.s, tt.max)
+			if got != tt.want {
+				t.Errorf("truncateString() = %v, want %v", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.max {
+				t.Errorf("truncateString() has %d runes, the limit is %d", n, tt.max)
+			}
+		})
+	}
+}
+
+func TestLogPool_SetLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      Limits
+		message     string
+		params      any
+		wantMessage string
+		wantParams  any
+	}{
+		{
+			name:        "message runes",
+			limits:      Limits{MaxMessageRunes: 25},
+			message:     "test message that is long enough to be truncated",
+			wantMessage: "test…[truncated 44 runes]",
+		},
+		{
+			name:       "string values",
+			limits:     Limits{MaxStringRunes: 25},
+			message:    "test",
+			params:     map[string]any{"body": []string{strings.Repeat("x", 30)}},
+			wantParams: map[string]any{"body": []any{"xxxx…[truncated 26 runes]"}},
+		},
+		{
+			name:    "collections",
+			limits:  Limits{MaxCollectionLen: 3},
+			message: "test",
+			params:  map[string]any{"a": 1, "b": []int{1, 2, 3, 4}, "c": 3, "d": 4},
+			wantParams: map[string]any{
+				"a": 1, "b": []any{1, 2, "…[truncated 2 elements]"}, "…": "[truncated 2 keys]",
+			},
+		},
+		{
+			name:    "params bytes",
+			limits:  Limits{MaxParamsBytes: 50},
+			message: "test",
+			params:  map[string]any{"body": strings.Repeat("x", 100)},
+			wantParams: map[string]any{
+				TruncatedKey: true,
+				"preview":    `{"body":"xxxxxx`,
+			},
+		},
+		{
+			name:       "params bytes without room for a preview",
+			limits:     Limits{MaxParamsBytes: 25},
+			message:    "test",
+			params:     map[string]any{"body": strings.Repeat("x", 100)},
+			wantParams: map[string]any{TruncatedKey: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+			l.SetLimits(&tt.limits)
+			m, _ := NewMessage(tt.message, LevelInfo)
+			m.SetParams(tt.params)
+			l.AddMessage(m)
+
+			if tt.wantMessage != "" && m.Message != tt.wantMessage {
+				t.Errorf("Message = %v, want %v", m.Message, tt.wantMessage)
+			}
+			if tt.wantParams != nil && !reflect.DeepEqual(m.Params, tt.wantParams) {
+				t.Errorf("Params = %#v, want %#v", m.Params, tt.wantParams)
+			}
+			if max := tt.limits.MaxMessageRunes; max > 0 && utf8.RuneCountInString(m.Message) > max {
+				t.Errorf("Message has %d runes, the limit is %d", utf8.RuneCountInString(m.Message), max)
+			}
+			if max := tt.limits.MaxParamsBytes; max > 0 {
+				if encoded, _ := json.Marshal(m.Params); len(encoded) > max {
+					t.Errorf("Params have %d bytes, the limit is %d: %s", len(encoded), max, encoded)
+				}
+			}
+		})
+	}
+
+	t.Run("instance default", func(t *testing.T) {
+		GetInstance().SetLimits(Limits{MaxMessageRunes: 25})
+		defer GetInstance().SetLimits(Limits{})
+
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+		l.Info("test message that is long enough to be truncated")
+		m := l.messages[0]
+		if m.Message != "test…[truncated 44 runes]" {
+			t.Errorf("Message = %v", m.Message)
+		}
+		if err := m.Validate(); err != nil {
+			t.Errorf("Validate() of the truncated message = %v, want nil", err)
+		}
+	})
+}
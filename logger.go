@@ -0,0 +1,191 @@
+package gokibilog
+
+import (
+	"fmt"
+)
+
+// componentKey is the params key set by [Logger.WithComponent].
+const componentKey = "component"
+
+// Logger is a lightweight view of [LogPool] that adds default params and partition to every message created through it.
+//
+// Loggers are immutable, With* methods return a child and leave the parent unchanged.
+type Logger struct {
+	pool      *LogPool
+	params    map[string]any
+	partition *Partition
+}
+
+// Logger returns [Logger] of [LogPool] without default params and partition.
+func (l *LogPool) Logger() *Logger {
+	return &Logger{pool: l}
+}
+
+// With returns a child [Logger] with keyvals merged into the default params.
+// keyvals are alternating keys and values, values of the child override values of the parent.
+func (g *Logger) With(keyvals ...any) *Logger {
+	child := g.clone()
+	for k, v := range keyvalsToParams(keyvals) {
+		child.params[k] = v
+	}
+	return child
+}
+
+// WithPartition returns a child [Logger] whose messages belong to the partition.
+// The partition is converted by [ToPartition], an invalid value is passed to [ErrorHandler]
+// and the child keeps the partition of the parent.
+func (g *Logger) WithPartition(partition any) *Logger {
+	child := g.clone()
+	p, err := ToPartition(partition)
+	if err != nil {
+		g.pool.handleError(fmt.Errorf("Invalid partition for \"%s\": %s", g.pool.logId, err.Error()))
+		return child
+	}
+	child.partition = p
+	return child
+}
+
+// WithComponent returns a child [Logger] with the "component" param.
+// Components of nested loggers are joined with a dot, for example "api.users".
+func (g *Logger) WithComponent(name string) *Logger {
+	if parent, ok := g.params[componentKey].(string); ok && parent != "" {
+		name = parent + "." + name
+	}
+	return g.With(componentKey, name)
+}
+
+// LogPool returns [LogPool] the messages are added to.
+func (g *Logger) LogPool() *LogPool {
+	return g.pool
+}
+
+// Enabled reports whether a message of the level will be accepted by [LogPool].
+func (g *Logger) Enabled(level MessageLevel) bool {
+	return g.pool.Enabled(level)
+}
+
+// AddMessage applies the default params and partition to the message and adds it to [LogPool].
+//
+// Keys of the message win over the default params. Params that are not a map[string]any are converted
+// by [EncodeParams] first, so structs get the default params too. Params that are still not a map, for example
+// a slice, are moved under the "params" key next to the default params.
+// The partition is applied only if the message has none.
+func (g *Logger) AddMessage(message *Message) {
+	if message != nil {
+		g.apply(message)
+	}
+	g.pool.AddMessage(message)
+}
+
+func (g *Logger) apply(m *Message) {
+	if m.Partition == nil && g.partition != nil {
+		partition := *g.partition
+		m.Partition = &partition
+	}
+	m.Params = addParams(m.Params, g.params)
+}
+
+func (g *Logger) clone() *Logger {
+	child := &Logger{
+		pool:      g.pool,
+		params:    make(map[string]any, len(g.params)),
+		partition: g.partition,
+	}
+	for k, v := range g.params {
+		child.params[k] = v
+	}
+	return child
+}
+
+func (g *Logger) log(level MessageLevel, text string, keyvals []any) {
+	if m := g.pool.newMessage(level, text, keyvals); m != nil {
+		g.AddMessage(m)
+	}
+}
+
+func (g *Logger) logf(level MessageLevel, format string, args []any) {
+	if !g.Enabled(level) {
+		return
+	}
+	g.log(level, fmt.Sprintf(format, args...), nil)
+}
+
+// Debug adds a [LevelDebug] message. keyvals are alternating keys and values of params.
+func (g *Logger) Debug(message string, keyvals ...any) {
+	g.log(LevelDebug, message, keyvals)
+}
+
+// Info adds a [LevelInfo] message. keyvals are alternating keys and values of params.
+func (g *Logger) Info(message string, keyvals ...any) {
+	g.log(LevelInfo, message, keyvals)
+}
+
+// Notice adds a [LevelNotice] message. keyvals are alternating keys and values of params.
+func (g *Logger) Notice(message string, keyvals ...any) {
+	g.log(LevelNotice, message, keyvals)
+}
+
+// Warning adds a [LevelWarning] message. keyvals are alternating keys and values of params.
+func (g *Logger) Warning(message string, keyvals ...any) {
+	g.log(LevelWarning, message, keyvals)
+}
+
+// Error adds a [LevelError] message. keyvals are alternating keys and values of params.
+func (g *Logger) Error(message string, keyvals ...any) {
+	g.log(LevelError, message, keyvals)
+}
+
+// Critical adds a [LevelCritical] message. keyvals are alternating keys and values of params.
+func (g *Logger) Critical(message string, keyvals ...any) {
+	g.log(LevelCritical, message, keyvals)
+}
+
+// Alert adds a [LevelAlert] message. keyvals are alternating keys and values of params.
+func (g *Logger) Alert(message string, keyvals ...any) {
+	g.log(LevelAlert, message, keyvals)
+}
+
+// Emergency adds a [LevelEmergency] message. keyvals are alternating keys and values of params.
+func (g *Logger) Emergency(message string, keyvals ...any) {
+	g.log(LevelEmergency, message, keyvals)
+}
+
+// Debugf adds a [LevelDebug] message formatted with fmt.Sprintf.
+func (g *Logger) Debugf(format string, args ...any) {
+	g.logf(LevelDebug, format, args)
+}
+
+// Infof adds a [LevelInfo] message formatted with fmt.Sprintf.
+func (g *Logger) Infof(format string, args ...any) {
+	g.logf(LevelInfo, format, args)
+}
+
+// Noticef adds a [LevelNotice] message formatted with fmt.Sprintf.
+func (g *Logger) Noticef(format string, args ...any) {
+	g.logf(LevelNotice, format, args)
+}
+
+// Warningf adds a [LevelWarning] message formatted with fmt.Sprintf.
+func (g *Logger) Warningf(format string, args ...any) {
+	g.logf(LevelWarning, format, args)
+}
+
+// Errorf adds a [LevelError] message formatted with fmt.Sprintf.
+func (g *Logger) Errorf(format string, args ...any) {
+	g.logf(LevelError, format, args)
+}
+
+// Criticalf adds a [LevelCritical] message formatted with fmt.Sprintf.
+func (g *Logger) Criticalf(format string, args ...any) {
+	g.logf(LevelCritical, format, args)
+}
+
+// Alertf adds a [LevelAlert] message formatted with fmt.Sprintf.
+func (g *Logger) Alertf(format string, args ...any) {
+	g.logf(LevelAlert, format, args)
+}
+
+// Emergencyf adds a [LevelEmergency] message formatted with fmt.Sprintf.
+func (g *Logger) Emergencyf(format string, args ...any) {
+	g.logf(LevelEmergency, format, args)
+}
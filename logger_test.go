@@ -0,0 +1,101 @@
+package gokibilog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLogger_With(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	l.SetErrorHandler(func(err error) {})
+	partition := "550e8400-e29b-11d4-a716-446655440000"
+
+	parent := l.Logger().With("userId", 1, "route", "/api").WithPartition(partition).WithComponent("api")
+	child := parent.With("userId", 2).WithComponent("users")
+
+	child.Info("test", "status", "sent")
+	parent.Info("test")
+
+	tests := []struct {
+		name       string
+		message    *Message
+		wantParams map[string]any
+	}{
+		{
+			name:       "child",
+			message:    l.messages[0],
+			wantParams: map[string]any{"userId": 2, "route": "/api", "component": "api.users", "status": "sent"},
+		},
+		{
+			name:       "parent unchanged",
+			message:    l.messages[1],
+			wantParams: map[string]any{"userId": 1, "route": "/api", "component": "api"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.message.Params, tt.wantParams) {
+				t.Errorf("Params = %v, want %v", tt.message.Params, tt.wantParams)
+			}
+			if tt.message.Partition == nil || tt.message.Partition.String() != partition {
+				t.Errorf("Partition = %v, want %v", tt.message.Partition, partition)
+			}
+		})
+	}
+}
+
+func TestLogger_AddMessage(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	g := l.Logger().With("userId", 1).WithPartition("550e8400-e29b-11d4-a716-446655440000")
+
+	t.Run("message keys win", func(t *testing.T) {
+		params := map[string]any{"userId": 2}
+		m, _ := NewMessage("test", LevelInfo)
+		m.SetParams(params)
+		g.AddMessage(m)
+		if !reflect.DeepEqual(m.Params, map[string]any{"userId": 2}) {
+			t.Errorf("Params = %v", m.Params)
+		}
+		if len(params) != 1 {
+			t.Errorf("AddMessage() modified the params of the caller: %v", params)
+		}
+	})
+
+	t.Run("own partition kept", func(t *testing.T) {
+		m, _ := NewMessage("test", LevelInfo)
+		m.SetPartition("1ec9414c-232a-6b00-b3c8-9e6bdeced846")
+		g.AddMessage(m)
+		if m.Partition.String() != "1ec9414c-232a-6b00-b3c8-9e6bdeced846" {
+			t.Errorf("Partition = %v", *m.Partition)
+		}
+	})
+
+	t.Run("array params wrapped", func(t *testing.T) {
+		m, _ := NewMessage("test", LevelInfo)
+		m.SetParams([]string{"sent"})
+		g.AddMessage(m)
+		if !reflect.DeepEqual(m.Params, map[string]any{"userId": 1, "params": []any{"sent"}}) {
+			t.Errorf("Params = %v", m.Params)
+		}
+	})
+
+	t.Run("struct params", func(t *testing.T) {
+		m, _ := NewMessage("test", LevelInfo)
+		m.SetParams(struct{ OrderID int }{OrderID: 7})
+		g.AddMessage(m)
+		if !reflect.DeepEqual(m.Params, map[string]any{"userId": 1, "OrderID": 7}) {
+			t.Errorf("Params = %v", m.Params)
+		}
+	})
+}
+
+func TestLogger_WithPartition(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	var errs []error
+	l.SetErrorHandler(func(err error) { errs = append(errs, err) })
+
+	g := l.Logger().WithPartition("not uuid")
+	if g.partition != nil || len(errs) != 1 {
+		t.Errorf("WithPartition() with an invalid value: partition %v, errors %v", g.partition, errs)
+	}
+}
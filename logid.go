@@ -0,0 +1,89 @@
+package gokibilog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// LogID is the identifier of a log in Kibilog.com, shaped like a ULID:
+// 26 characters of the Crockford base32 alphabet encoding 128 bits, for example "01hggahp9skcph42wknxbckb46".
+type LogID [16]byte
+
+// logIDAlphabet is the Crockford base32 alphabet in the lower case used by Kibilog.com.
+const logIDAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// logIDLength is the length of the text form of [LogID].
+const logIDLength = 26
+
+// ParseLogID parses the text form of [LogID].
+// The whole string must be the ID: exactly 26 characters of the Crockford base32 alphabet in any case,
+// the first one from 0 to 7, so that the value fits 128 bits.
+func ParseLogID(s string) (LogID, error) {
+	if len(s) != logIDLength {
+		return LogID{}, fmt.Errorf("The \"%s\" is not similar to the log id Kibilog.com: the length is %d, want %d", s, len(s), logIDLength)
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(logIDAlphabet, lowerASCII(s[i]))
+		if v < 0 {
+			return LogID{}, fmt.Errorf("The \"%s\" is not similar to the log id Kibilog.com: invalid character %q", s, s[i])
+		}
+		if i == 0 && v > 7 {
+			return LogID{}, fmt.Errorf("The \"%s\" is not similar to the log id Kibilog.com: the first character must be from 0 to 7", s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	var id LogID
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, nil
+}
+
+// MustParseLogID is like [ParseLogID] but panics if the string cannot be parsed.
+func MustParseLogID(s string) LogID {
+	id, err := ParseLogID(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// String returns the text form of [LogID] in the lower case, as Kibilog.com shows it.
+func (id LogID) String() string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var b [logIDLength]byte
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = logIDAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+// IsZero reports whether the ID is the zero value.
+func (id LogID) IsZero() bool {
+	return id == LogID{}
+}
+
+func (id LogID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func (id *LogID) UnmarshalText(text []byte) error {
+	parsed, err := ParseLogID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
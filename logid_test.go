@@ -0,0 +1,60 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLogID(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", s: "01hggahp9skcph42wknxbckb46", want: "01hggahp9skcph42wknxbckb46"},
+		{name: "upper case", s: "01HGGAHP9SKCPH42WKNXBCKB46", want: "01hggahp9skcph42wknxbckb46"},
+		{name: "max", s: "7zzzzzzzzzzzzzzzzzzzzzzzzz", want: "7zzzzzzzzzzzzzzzzzzzzzzzzz"},
+		{name: "embedded in a longer string", s: "log 01hggahp9skcph42wknxbckb46", wantErr: true},
+		{name: "too long", s: "01hggahp9skcph42wknxbckb460", wantErr: true},
+		{name: "too short", s: "01hggahp9skcph42wknxbckb4", wantErr: true},
+		{name: "overflow", s: "81hggahp9skcph42wknxbckb46", wantErr: true},
+		{name: "excluded letter", s: "01hggahp9skcph42wknxbckbu6", wantErr: true},
+		{name: "empty", s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLogID(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLogID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseLogID().String() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLogID_JSON(t *testing.T) {
+	id := MustParseLogID("01hggahp9skcph42wknxbckb46")
+	data, err := json.Marshal(id)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if string(data) != `"01hggahp9skcph42wknxbckb46"` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+	var got LogID
+	if err := json.Unmarshal(data, &got); err != nil || got != id {
+		t.Errorf("json.Unmarshal() = %v, %v, want %v", got, err, id)
+	}
+	if err := json.Unmarshal([]byte(`"not a log id"`), &got); err == nil {
+		t.Errorf("json.Unmarshal() of an invalid id returned no error")
+	}
+}
+
+func TestNewLogPool_strict(t *testing.T) {
+	if _, err := NewLogPool("https://kibilog.com/log/01hggahp9skcph42wknxbckb46"); err == nil {
+		t.Errorf("NewLogPool() accepted a log id embedded in a longer string")
+	}
+}
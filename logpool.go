@@ -1,30 +1,191 @@
 package gokibilog
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type LogPool struct {
 	mu       sync.Mutex
-	logId    string
+	logId    LogID
 	messages []*Message
+	// minLevel is nil while the pool uses the default of [Kibilog].
+	minLevel     atomic.Pointer[MessageLevel]
+	errorHandler atomic.Pointer[ErrorHandler]
+	caller       atomic.Pointer[callerCapture]
+	limits       atomic.Pointer[Limits]
+	// defaultParams, maxMessages and messageTTL are guarded by mu.
+	defaultParams map[string]any
+	maxMessages   int
+	messageTTL    time.Duration
 }
 
 // AddMessage is a method for filling [LogPool] with messages
+//
+// If the message has no sequence number yet, it is assigned here.
+// Messages with a level below [LogPool.GetMinLevel] are discarded, too long values are truncated by [Limits].
+// Messages failing [Message.Validate] are passed to [ErrorHandler] and [DeadLetterHandler] instead of being added.
 func (l *LogPool) AddMessage(message *Message) {
+	if message != nil && !l.Enabled(message.Level) {
+		return
+	}
+	if message != nil && message.Sequence == 0 {
+		message.Sequence = nextSequence()
+	}
+	if message != nil {
+		l.captureCaller(message)
+		l.applyLimits(message)
+		if err := l.validateMessage(message); err != nil {
+			l.handleError(err)
+			return
+		}
+	}
 	l.mu.Lock()
-	defer l.mu.Unlock()
 	l.messages = append(l.messages, message)
+	overflow := l.trimOverflow()
+	l.mu.Unlock()
+
+	for _, m := range overflow {
+		GetInstance().deadLetter(l.logId, m, DeadLetterOverflow, nil)
+	}
+}
+
+// requeue puts messages that failed to send back in front of the messages added since.
+func (l *LogPool) requeue(messages []*Message) {
+	l.mu.Lock()
+	l.messages = append(append([]*Message{}, messages...), l.messages...)
+	overflow := l.trimOverflow()
+	l.mu.Unlock()
+
+	for _, m := range overflow {
+		GetInstance().deadLetter(l.logId, m, DeadLetterOverflow, nil)
+	}
+}
+
+// trimOverflow drops the oldest messages above the limit of [LogPool.SetMaxMessages] and returns them.
+// The caller must hold the lock.
+func (l *LogPool) trimOverflow() (overflow []*Message) {
+	if l.maxMessages <= 0 || len(l.messages) <= l.maxMessages {
+		return nil
+	}
+	overflow = l.messages[:len(l.messages)-l.maxMessages]
+	l.messages = append([]*Message{}, l.messages[len(l.messages)-l.maxMessages:]...)
+	return overflow
+}
+
+// validateMessage validates the message like [validatePool] does before sending and dead-letters it if it is invalid.
+func (l *LogPool) validateMessage(m *Message) error {
+	// A resubmitted message may have been changed, so it is validated in full again.
+	m.validated = false
+	reason, err := l.checkMessage(m)
+	if err != nil {
+		GetInstance().deadLetter(l.logId, m, reason, err)
+		return err
+	}
+	m.validated = true
+	return nil
+}
+
+// checkMessage validates the message and returns the reason to dead-letter it if it is invalid.
+//
+// Params of messages validated on add are not encoded again, they are checked when sending instead,
+// see [client.Send].
+func (l *LogPool) checkMessage(m *Message) (DeadLetterReason, error) {
+	k := GetInstance()
+	// The text is truncated by applyLimits on add, so only the total size is limited here.
+	maxBytes := k.maxMessageBytes.Load()
+	params := !m.validated
+	err := m.validate(0, maxBytes, params)
+	if errors.Is(err, ErrUnencodableParams) && k.lenientParams.Load() {
+		sanitizeMessage(m)
+		err = m.validate(0, maxBytes, params)
+	}
+	if err == nil {
+		return "", nil
+	}
+	reason := DeadLetterValidation
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) && validationErr.oversizeOnly() {
+		reason = DeadLetterOversize
+	}
+	return reason, fmt.Errorf("Error in the message for \"%s\": %w", l.logId, err)
+}
+
+// SetMaxMessages limits the number of messages kept in [LogPool]. When it is full, the oldest messages are dropped
+// and passed to [DeadLetterHandler] with [DeadLetterOverflow]. 0 means no limit, which is the default.
+func (l *LogPool) SetMaxMessages(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxMessages = n
+}
+
+// SetMessageTTL sets how old a message may be to still be sent, judging by its CreatedAt.
+// Older messages are dropped when sending and passed to [DeadLetterHandler] with [DeadLetterExpired].
+// 0 means no limit, which is the default.
+func (l *LogPool) SetMessageTTL(ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messageTTL = ttl
+}
+
+// removeExpiredMessages drops the messages older than the TTL and returns them to be dead-lettered
+// after unlocking. The caller must hold the lock.
+func (l *LogPool) removeExpiredMessages() (expired []pendingLetter) {
+	if l.messageTTL <= 0 {
+		return nil
+	}
+	deadline := GetInstance().GetClock().Now().Add(-l.messageTTL)
+	for i, m := range l.messages {
+		if m == nil {
+			continue
+		}
+		if createdAt, ok := m.createdAtTime(); !ok || !createdAt.Before(deadline) {
+			continue
+		}
+		expired = append(expired, pendingLetter{message: m, reason: DeadLetterExpired})
+		l.messages[i] = nil
+	}
+	l.removeNilMessages()
+	return expired
+}
+
+// SetMinLevel sets the minimum level of messages accepted by [LogPool.AddMessage].
+// It can be changed at runtime from any goroutine.
+func (l *LogPool) SetMinLevel(level MessageLevel) {
+	l.minLevel.Store(&level)
+}
+
+// ResetMinLevel makes [LogPool] use the default minimum level of [Kibilog] again.
+func (l *LogPool) ResetMinLevel() {
+	l.minLevel.Store(nil)
+}
+
+// GetMinLevel returns the minimum level of [LogPool], or the default of [Kibilog] if it was not set.
+func (l *LogPool) GetMinLevel() MessageLevel {
+	if level := l.minLevel.Load(); level != nil {
+		return *level
+	}
+	return GetInstance().GetMinLevel()
+}
+
+// Enabled reports whether a message of the level will be accepted by [LogPool].
+// Use it to skip building expensive params.
+func (l *LogPool) Enabled(level MessageLevel) bool {
+	return level >= l.GetMinLevel()
 }
 
 func (l *LogPool) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	return len(l.messages)
 }
 
-func (l *LogPool) getLogId() string {
+// GetLogID returns [LogID] of the log the messages are sent to.
+func (l *LogPool) GetLogID() LogID {
 	return l.logId
 }
 
@@ -39,14 +200,15 @@ func (l *LogPool) removeNilMessages() {
 }
 
 // Create new LogPool
+//
+// The logId is parsed by [ParseLogID], the whole string must be the log id.
 func NewLogPool(logId string) (*LogPool, error) {
-	logId = strings.Trim(logId, " ")
-	reg := regexp.MustCompile("[0-7][0-9a-hjkmnp-tv-z]{25}")
-	if !reg.MatchString(logId) {
-		return nil, fmt.Errorf("The \"%s\" is not similar to the log id Kibilog.com", logId)
+	id, err := ParseLogID(strings.Trim(logId, " "))
+	if err != nil {
+		return nil, err
 	}
 	l := LogPool{
-		logId: logId,
+		logId: id,
 	}
 	l.messages = []*Message{}
 	return &l, nil
@@ -0,0 +1,137 @@
+package gokibilog
+
+import (
+	"fmt"
+)
+
+// badKey is used as the key of a value in keyvals that has no key.
+const badKey = "!BADKEY"
+
+// keyvalsToParams converts alternating keys and values into params.
+// Keys that are not strings are formatted with fmt.Sprint.
+func keyvalsToParams(keyvals []any) map[string]any {
+	params := make(map[string]any, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			params[badKey] = keyvals[i]
+			break
+		}
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		params[key] = keyvals[i+1]
+	}
+	return params
+}
+
+// log creates [Message] and adds it to [LogPool]. Problems are passed to [ErrorHandler].
+func (l *LogPool) log(level MessageLevel, text string, keyvals []any) {
+	if m := l.newMessage(level, text, keyvals); m != nil {
+		l.AddMessage(m)
+	}
+}
+
+func (l *LogPool) logf(level MessageLevel, format string, args []any) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.log(level, fmt.Sprintf(format, args...), nil)
+}
+
+// newMessage creates [Message] with params from keyvals.
+// It returns nil if the level is disabled or the message is invalid.
+func (l *LogPool) newMessage(level MessageLevel, text string, keyvals []any) *Message {
+	if !l.Enabled(level) {
+		return nil
+	}
+	m, err := NewMessage(text, level)
+	if err != nil {
+		l.handleError(fmt.Errorf("Error in the message for \"%s\": %s", l.logId, err.Error()))
+		return nil
+	}
+	if len(keyvals) > 0 {
+		m.SetParams(keyvalsToParams(keyvals))
+	}
+	return m
+}
+
+// Debug adds a [LevelDebug] message. keyvals are alternating keys and values of params, for example "orderId", 123456.
+func (l *LogPool) Debug(message string, keyvals ...any) {
+	l.log(LevelDebug, message, keyvals)
+}
+
+// Info adds a [LevelInfo] message. keyvals are alternating keys and values of params.
+func (l *LogPool) Info(message string, keyvals ...any) {
+	l.log(LevelInfo, message, keyvals)
+}
+
+// Notice adds a [LevelNotice] message. keyvals are alternating keys and values of params.
+func (l *LogPool) Notice(message string, keyvals ...any) {
+	l.log(LevelNotice, message, keyvals)
+}
+
+// Warning adds a [LevelWarning] message. keyvals are alternating keys and values of params.
+func (l *LogPool) Warning(message string, keyvals ...any) {
+	l.log(LevelWarning, message, keyvals)
+}
+
+// Error adds a [LevelError] message. keyvals are alternating keys and values of params.
+func (l *LogPool) Error(message string, keyvals ...any) {
+	l.log(LevelError, message, keyvals)
+}
+
+// Critical adds a [LevelCritical] message. keyvals are alternating keys and values of params.
+func (l *LogPool) Critical(message string, keyvals ...any) {
+	l.log(LevelCritical, message, keyvals)
+}
+
+// Alert adds a [LevelAlert] message. keyvals are alternating keys and values of params.
+func (l *LogPool) Alert(message string, keyvals ...any) {
+	l.log(LevelAlert, message, keyvals)
+}
+
+// Emergency adds a [LevelEmergency] message. keyvals are alternating keys and values of params.
+func (l *LogPool) Emergency(message string, keyvals ...any) {
+	l.log(LevelEmergency, message, keyvals)
+}
+
+// Debugf adds a [LevelDebug] message formatted with fmt.Sprintf.
+func (l *LogPool) Debugf(format string, args ...any) {
+	l.logf(LevelDebug, format, args)
+}
+
+// Infof adds a [LevelInfo] message formatted with fmt.Sprintf.
+func (l *LogPool) Infof(format string, args ...any) {
+	l.logf(LevelInfo, format, args)
+}
+
+// Noticef adds a [LevelNotice] message formatted with fmt.Sprintf.
+func (l *LogPool) Noticef(format string, args ...any) {
+	l.logf(LevelNotice, format, args)
+}
+
+// Warningf adds a [LevelWarning] message formatted with fmt.Sprintf.
+func (l *LogPool) Warningf(format string, args ...any) {
+	l.logf(LevelWarning, format, args)
+}
+
+// Errorf adds a [LevelError] message formatted with fmt.Sprintf.
+func (l *LogPool) Errorf(format string, args ...any) {
+	l.logf(LevelError, format, args)
+}
+
+// Criticalf adds a [LevelCritical] message formatted with fmt.Sprintf.
+func (l *LogPool) Criticalf(format string, args ...any) {
+	l.logf(LevelCritical, format, args)
+}
+
+// Alertf adds a [LevelAlert] message formatted with fmt.Sprintf.
+func (l *LogPool) Alertf(format string, args ...any) {
+	l.logf(LevelAlert, format, args)
+}
+
+// Emergencyf adds a [LevelEmergency] message formatted with fmt.Sprintf.
+func (l *LogPool) Emergencyf(format string, args ...any) {
+	l.logf(LevelEmergency, format, args)
+}
@@ -0,0 +1,90 @@
+package gokibilog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_keyvalsToParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyvals []any
+		want    map[string]any
+	}{
+		{
+			name:    "pairs",
+			keyvals: []any{"orderId", 123456, "status", "sent"},
+			want:    map[string]any{"orderId": 123456, "status": "sent"},
+		},
+		{
+			name:    "not string key",
+			keyvals: []any{1, true},
+			want:    map[string]any{"1": true},
+		},
+		{
+			name:    "missing value",
+			keyvals: []any{"orderId", 123456, "lost"},
+			want:    map[string]any{"orderId": 123456, badKey: "lost"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyvalsToParams(tt.keyvals); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keyvalsToParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogPool_LevelMethods(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	methods := []struct {
+		level MessageLevel
+		log   func(string, ...any)
+		logf  func(string, ...any)
+	}{
+		{LevelDebug, l.Debug, l.Debugf},
+		{LevelInfo, l.Info, l.Infof},
+		{LevelNotice, l.Notice, l.Noticef},
+		{LevelWarning, l.Warning, l.Warningf},
+		{LevelError, l.Error, l.Errorf},
+		{LevelCritical, l.Critical, l.Criticalf},
+		{LevelAlert, l.Alert, l.Alertf},
+		{LevelEmergency, l.Emergency, l.Emergencyf},
+	}
+	for _, m := range methods {
+		t.Run(m.level.String(), func(t *testing.T) {
+			l.messages = []*Message{}
+			m.log("test", "orderId", 123456)
+			m.logf("order %d", 123456)
+			if l.Len() != 2 {
+				t.Fatalf("Messages count = %v, want 2", l.Len())
+			}
+			if l.messages[0].Level != m.level || l.messages[1].Level != m.level {
+				t.Errorf("Level = %v, %v, want %v", l.messages[0].Level, l.messages[1].Level, m.level)
+			}
+			if !reflect.DeepEqual(l.messages[0].Params, map[string]any{"orderId": 123456}) {
+				t.Errorf("Params = %v", l.messages[0].Params)
+			}
+			if l.messages[1].Message != "order 123456" {
+				t.Errorf("Message = %v, want \"order 123456\"", l.messages[1].Message)
+			}
+		})
+	}
+}
+
+func TestLogPool_SetErrorHandler(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+
+	var poolErrs, instanceErrs []error
+	GetInstance().SetErrorHandler(func(err error) { instanceErrs = append(instanceErrs, err) })
+	defer GetInstance().SetErrorHandler(nil)
+
+	l.Info("")
+	l.SetErrorHandler(func(err error) { poolErrs = append(poolErrs, err) })
+	l.Info(" ")
+
+	if len(instanceErrs) != 1 || len(poolErrs) != 1 || l.Len() != 0 {
+		t.Errorf("Errors: instance %v, pool %v, messages %v", instanceErrs, poolErrs, l.Len())
+	}
+}
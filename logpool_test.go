@@ -94,8 +94,8 @@ func TestLogPool_getLogId(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			l, _ := NewLogPool(tt.args.logId)
-			if l.getLogId() != tt.args.logId {
-				t.Errorf("getLogId() = %v, want %v", l.getLogId(), tt.args.logId)
+			if l.GetLogID().String() != tt.args.logId {
+				t.Errorf("GetLogID() = %v, want %v", l.GetLogID(), tt.args.logId)
 			}
 		})
 	}
@@ -161,3 +161,65 @@ func TestLogPool_removeNilMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestLogPool_MinLevel(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceMin  MessageLevel
+		poolMin      *MessageLevel
+		levels       []MessageLevel
+		messageCount int
+	}{
+		{
+			name:         "everything by default",
+			levels:       []MessageLevel{LevelDebug, LevelInfo, LevelError},
+			messageCount: 3,
+		},
+		{
+			name:         "instance default",
+			instanceMin:  LevelInfo,
+			levels:       []MessageLevel{LevelDebug, LevelInfo, LevelError},
+			messageCount: 2,
+		},
+		{
+			name:        "pool overrides instance",
+			instanceMin: LevelInfo,
+			poolMin: func() *MessageLevel {
+				l := LevelError
+				return &l
+			}(),
+			levels:       []MessageLevel{LevelDebug, LevelInfo, LevelError},
+			messageCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			GetInstance().SetMinLevel(tt.instanceMin)
+			defer GetInstance().SetMinLevel(0)
+
+			l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+			if tt.poolMin != nil {
+				l.SetMinLevel(*tt.poolMin)
+			}
+			for _, level := range tt.levels {
+				m, _ := NewMessage("test", level)
+				l.AddMessage(m)
+			}
+			if l.Len() != tt.messageCount {
+				t.Errorf("AddMessage(): messages count = %v, want %v", l.Len(), tt.messageCount)
+			}
+		})
+	}
+
+	t.Run("reset and enabled", func(t *testing.T) {
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+		l.SetMinLevel(LevelWarning)
+		if l.Enabled(LevelInfo) || !l.Enabled(LevelWarning) {
+			t.Errorf("Enabled() does not respect the minimum level %v", l.GetMinLevel())
+		}
+		l.ResetMinLevel()
+		if !l.Enabled(LevelDebug) {
+			t.Errorf("Enabled(LevelDebug) = false after ResetMinLevel()")
+		}
+	})
+}
@@ -1,12 +1,9 @@
 package gokibilog
 
 import (
-	"errors"
-	"fmt"
-	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
-	"unicode/utf8"
 )
 
 type MessageLevel int
@@ -24,12 +21,27 @@ const (
 )
 
 // [Message] stores information that will be transmitted to the log in Kibilog.com
+//
+// Sequence is a per-process monotonic number, so messages created within the same second can always be ordered.
 type Message struct {
 	Message   string       `json:"message"`
 	CreatedAt *int64       `json:"createdAt"`
 	Level     MessageLevel `json:"level"`
 	Params    any          `json:"params"`
-	Partition *string      `json:"partition"`
+	Partition *Partition   `json:"partition"`
+	Sequence  uint64       `json:"sequence"`
+	// precision is the unit CreatedAt was stored in, it is converted to the current one when sending.
+	precision TimePrecision
+	// validated is set when [LogPool.AddMessage] has fully validated the message,
+	// so that only the cheap checks are repeated before sending.
+	validated bool
+}
+
+// sequence is a per-process monotonic counter of messages.
+var sequence atomic.Uint64
+
+func nextSequence() uint64 {
+	return sequence.Add(1)
 }
 
 // The text of the message to be saved.
@@ -38,12 +50,26 @@ func (m *Message) SetMessage(message string) {
 }
 
 // The time that the message will display. It is assumed that it indicates the time when the message occurred.
+// By default, [NewMessage] stamps it with the creation time of the message.
 // If it is not passed, we will substitute a value equal to the time we received the request.
+//
+// The time is stored in the unit set by [Kibilog.SetTimePrecision], seconds by default.
+// If the precision is changed later, the time is converted to the new unit when sending.
+// CreatedAt assigned directly is taken in seconds.
 func (m *Message) SetCreatedAt(createdAt time.Time) {
-	createdAtInt := createdAt.UTC().Unix()
+	m.precision = GetInstance().GetTimePrecision()
+	createdAtInt := m.precision.unix(createdAt)
 	m.CreatedAt = &createdAtInt
 }
 
+// createdAtTime decodes CreatedAt with the unit it was stored in.
+func (m *Message) createdAtTime() (createdAt time.Time, ok bool) {
+	if m.CreatedAt == nil {
+		return time.Time{}, false
+	}
+	return m.precision.time(*m.CreatedAt), true
+}
+
 // [Message] level according to RFC 5424 standard.
 //
 // Available value:
@@ -63,6 +89,8 @@ func (m *Message) SetCreatedAt(createdAt time.Time) {
 // - [LevelAlert]
 //
 // - [LevelEmergency]
+//
+// Other values are reported by [Message.Validate].
 func (m *Message) SetLevel(level MessageLevel) {
 	m.Level = level
 }
@@ -70,45 +98,44 @@ func (m *Message) SetLevel(level MessageLevel) {
 // If necessary, additional parameters can be registered to form an array with scalar values.
 // The transmitted value must be able to be processed via "encoding/json".
 // Available values: ~array, ~map, ~struct
+// Errors, Stringers and other special values are converted by [EncodeParams] at send time.
+// To send only scalar values under dotted key paths, enable [Kibilog.SetFlattenParams].
 func (m *Message) SetParams(params any) {
 	m.Params = params
 }
 
 // If we need to group messages, we need to form a message partition value.
-// The partition value must be strings of UUID or nil.
+// The partition value is converted by [ToPartition]: a UUID string, [Partition], [16]byte, [fmt.Stringer] or nil.
 func (m *Message) SetPartition(partition any) error {
-	switch pType := partition.(type) {
-	case string:
-		pStr := strings.ToLower(strings.Trim(partition.(string), " "))
-		if utf8.RuneCountInString(pStr) != 36 {
-			return fmt.Errorf("The length of the UUID is 36, the length of the transmitted value is %d.", utf8.RuneCountInString(pStr))
-		}
-		reg := regexp.MustCompile("[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}")
-		if !reg.MatchString(pStr) {
-			return fmt.Errorf("The passed value does not look like a UUID.")
-		}
-		m.Partition = &pStr
-	case nil:
-		m.Partition = nil
-		return nil
-	default:
-		return fmt.Errorf("Partition must be string or nil, typed %v.", pType)
+	p, err := ToPartition(partition)
+	if err != nil {
+		return err
 	}
+	m.Partition = p
 	return nil
 }
 
 // NewMessage create new [Message]
+//
+// It returns [*ValidationError] if the text is empty or the level is unknown, see [Message.Validate].
+//
+// CreatedAt is stamped with the time of the [Clock] of [Kibilog], unless disabled by [Kibilog.SetAutoCreatedAt].
 func NewMessage(message string, level MessageLevel) (*Message, error) {
 	message = strings.Trim(message, "\r\n\t ")
-	if utf8.RuneCountInString(message) < 1 {
-		return nil, errors.New("the message cannot be empty")
-	}
 	m := Message{
 		Message:   message,
 		CreatedAt: nil,
 		Level:     level,
 		Params:    nil,
 		Partition: nil,
+		Sequence:  nextSequence(),
+	}
+	// The size limits are applied later by LogPool.AddMessage, which truncates too long values.
+	if err := m.validate(0, 0, true); err != nil {
+		return nil, err
+	}
+	if createdAt, ok := GetInstance().createdAt(); ok {
+		m.SetCreatedAt(createdAt)
 	}
 	return &m, nil
 }
@@ -105,9 +105,22 @@ func TestMessage_SetLevel(t *testing.T) {
 func TestMessage_SetCreatedAt(t *testing.T) {
 	loc, _ := time.LoadLocation("Europe/Moscow")
 
-	t.Run("nil default", func(t *testing.T) {
+	t.Run("stamped default", func(t *testing.T) {
+		clock := NewManualClock(time.Date(2024, 01, 30, 15, 16, 59, 0, loc))
+		GetInstance().SetClock(clock)
+		defer GetInstance().SetClock(nil)
+
 		m, _ := NewMessage("test", LevelDebug)
+		if m.CreatedAt == nil || *m.CreatedAt != clock.Now().UTC().Unix() {
+			t.Errorf("SetCreatedAt() = %#v, want %#v", m.CreatedAt, clock.Now().UTC().Unix())
+		}
+	})
 
+	t.Run("nil when disabled", func(t *testing.T) {
+		GetInstance().SetAutoCreatedAt(false)
+		defer GetInstance().SetAutoCreatedAt(true)
+
+		m, _ := NewMessage("test", LevelDebug)
 		if m.CreatedAt != nil {
 			t.Errorf("SetCreatedAt() = %#v, want nil", m.CreatedAt)
 		}
@@ -140,7 +153,7 @@ func TestMessage_SetPartition(t *testing.T) {
 		if err != nil {
 			t.Errorf("Error: %s", err.Error())
 		}
-		if *m.Partition != uuid {
+		if m.Partition.String() != uuid {
 			t.Errorf("SetPartition() = %v, want %v", *m.Partition, uuid)
 		}
 	})
@@ -152,7 +165,7 @@ func TestMessage_SetPartition(t *testing.T) {
 		if err != nil {
 			t.Errorf("Error: %s", err.Error())
 		}
-		if *m.Partition != strings.ToLower(uuid) {
+		if m.Partition.String() != strings.ToLower(uuid) {
 			t.Errorf("SetPartition() = %v, want %v", *m.Partition, strings.ToLower(uuid))
 		}
 	})
@@ -177,3 +190,22 @@ func TestMessage_SetPartition(t *testing.T) {
 		}
 	})
 }
+
+func TestMessage_Sequence(t *testing.T) {
+	t.Run("monotonic", func(t *testing.T) {
+		m1, _ := NewMessage("test 1", LevelDebug)
+		m2, _ := NewMessage("test 2", LevelDebug)
+		if m1.Sequence == 0 || m2.Sequence <= m1.Sequence {
+			t.Errorf("Sequence = %v, %v, want increasing values", m1.Sequence, m2.Sequence)
+		}
+	})
+
+	t.Run("assigned on add", func(t *testing.T) {
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+		m := &Message{Message: "test", Level: LevelDebug}
+		l.AddMessage(m)
+		if m.Sequence == 0 {
+			t.Errorf("AddMessage() did not assign a sequence number")
+		}
+	})
+}
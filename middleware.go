@@ -0,0 +1,203 @@
+package gokibilog
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DefaultPartitionHeader is the header used to pass the partition between services.
+const DefaultPartitionHeader = "X-Kibilog-Partition"
+
+// MiddlewareOptions configures [Middleware].
+type MiddlewareOptions struct {
+	// PartitionHeader is the request header to take the partition from. [DefaultPartitionHeader] if empty.
+	// If the header is missing or is not a UUID, a new partition is generated.
+	PartitionHeader string
+	// PartitionFromTraceparent makes the partition be derived by [PartitionFromTraceparent]
+	// when the partition header is missing or invalid.
+	PartitionFromTraceparent bool
+	// RequestBodyLimit is the maximum number of bytes of the request body to capture. 0 disables capturing.
+	RequestBodyLimit int
+	// ResponseBodyLimit is the maximum number of bytes of the response body to capture. 0 disables capturing.
+	ResponseBodyLimit int
+	// TrustProxyHeaders makes the client IP be taken from X-Forwarded-For or X-Real-IP.
+	TrustProxyHeaders bool
+}
+
+// Middleware returns net/http middleware that logs every request into its own partition of [LogPool].
+//
+// The message contains the method, path, status, duration, sizes, client IP and, if enabled, the bodies.
+// Its level depends on the status: [LevelError] for 5xx, [LevelWarning] for 4xx, [LevelInfo] otherwise.
+// The partition is put into the request context, so the handler can log into it with [LogPool.InfoContext] and others.
+func Middleware(pool *LogPool, opts MiddlewareOptions) func(http.Handler) http.Handler {
+	if opts.PartitionHeader == "" {
+		opts.PartitionHeader = DefaultPartitionHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := GetInstance().GetClock().Now()
+
+			partition, ok := partitionFromRequest(r, opts.PartitionHeader, opts.PartitionFromTraceparent)
+			if !ok {
+				partition = newPartition()
+			}
+			ctx, _ := ContextWithPartition(r.Context(), partition)
+			r = r.WithContext(ctx)
+
+			var reqBody *captureReader
+			if r.Body != nil && r.Body != http.NoBody {
+				reqBody = &captureReader{ReadCloser: r.Body, capture: capture{limit: opts.RequestBodyLimit}}
+				r.Body = reqBody
+			}
+			rw := &responseRecorder{ResponseWriter: w, capture: capture{limit: opts.ResponseBodyLimit}, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			params := map[string]any{
+				"method":       r.Method,
+				"path":         r.URL.Path,
+				"status":       rw.status,
+				"durationMs":   float64(GetInstance().GetClock().Now().Sub(start).Microseconds()) / 1000,
+				"responseSize": rw.size,
+				"clientIp":     clientIP(r, opts.TrustProxyHeaders),
+			}
+			if r.URL.RawQuery != "" {
+				params["query"] = r.URL.RawQuery
+			}
+			requestSize := r.ContentLength
+			if reqBody != nil && reqBody.size > requestSize {
+				requestSize = reqBody.size
+			}
+			if requestSize > 0 {
+				params["requestSize"] = requestSize
+			}
+			if reqBody != nil && opts.RequestBodyLimit > 0 {
+				params["requestBody"] = reqBody.buf.String()
+				if reqBody.truncated {
+					params["requestBodyTruncated"] = true
+				}
+			}
+			if opts.ResponseBodyLimit > 0 {
+				params["responseBody"] = rw.buf.String()
+				if rw.truncated {
+					params["responseBodyTruncated"] = true
+				}
+			}
+
+			if m := pool.newMessage(levelByStatus(rw.status), r.Method+" "+r.URL.Path, nil); m != nil {
+				m.SetParams(params)
+				pool.contextLogger(ctx).AddMessage(m)
+			}
+		})
+	}
+}
+
+// levelByStatus returns the level of a message about an HTTP response with the status.
+func levelByStatus(status int) MessageLevel {
+	switch {
+	case status >= 500:
+		return LevelError
+	case status >= 400:
+		return LevelWarning
+	}
+	return LevelInfo
+}
+
+func clientIP(r *http.Request, trustProxyHeaders bool) string {
+	if trustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return strings.TrimSpace(realIP)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// capture keeps up to limit bytes written to it.
+type capture struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (c *capture) write(p []byte) {
+	if c.limit <= 0 {
+		return
+	}
+	if free := c.limit - c.buf.Len(); free < len(p) {
+		c.truncated = c.truncated || len(p) > 0
+		p = p[:max(free, 0)]
+	}
+	c.buf.Write(p)
+}
+
+// captureReader counts and captures the request body read by the handler.
+type captureReader struct {
+	io.ReadCloser
+	capture
+	size int64
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.size += int64(n)
+	r.write(p[:n])
+	return n, err
+}
+
+// responseRecorder records the status, size and body of the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	capture
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	w.write(p[:n])
+	return n, err
+}
+
+// Flush implements [http.Flusher] for streaming handlers, it does nothing if the original writer cannot flush.
+func (w *responseRecorder) Flush() {
+	w.wroteHeader = true
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements [http.Hijacker] for handlers taking over the connection, for example websockets.
+// The response is logged with status 101 unless the handler has written another one.
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap allows [http.ResponseController] to reach the original [http.ResponseWriter].
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,164 @@
+package gokibilog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	partition := "550e8400-e29b-11d4-a716-446655440000"
+	tests := []struct {
+		name       string
+		opts       MiddlewareOptions
+		header     string
+		status     int
+		wantLevel  MessageLevel
+		wantParams map[string]any
+	}{
+		{
+			name:      "ok with header partition",
+			header:    partition,
+			status:    http.StatusOK,
+			wantLevel: LevelInfo,
+			wantParams: map[string]any{
+				"method":       http.MethodPost,
+				"path":         "/api/user/create",
+				"status":       http.StatusOK,
+				"responseSize": int64(17),
+				"requestSize":  int64(20),
+				"clientIp":     "192.0.2.1",
+			},
+		},
+		{
+			name:      "bodies and proxy",
+			opts:      MiddlewareOptions{RequestBodyLimit: 5, ResponseBodyLimit: 100, TrustProxyHeaders: true},
+			status:    http.StatusBadRequest,
+			wantLevel: LevelWarning,
+			wantParams: map[string]any{
+				"requestBody":          `{"nam`,
+				"requestBodyTruncated": true,
+				"responseBody":         `{"done":1,"id":1}`,
+				"clientIp":             "203.0.113.7",
+			},
+		},
+		{
+			name:      "server error",
+			status:    http.StatusInternalServerError,
+			wantLevel: LevelError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+			var handlerPartition Partition
+			handler := Middleware(l, tt.opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerPartition, _ = PartitionFromContext(r.Context())
+				io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, `{"done":1,"id":1}`)
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/api/user/create", strings.NewReader(`{"name":"John Doe"}`+"\n"))
+			r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+			if tt.header != "" {
+				r.Header.Set(DefaultPartitionHeader, tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if l.Len() != 1 {
+				t.Fatalf("Messages count = %v, want 1", l.Len())
+			}
+			m := l.messages[0]
+			if m.Level != tt.wantLevel {
+				t.Errorf("Level = %v, want %v", m.Level, tt.wantLevel)
+			}
+			if m.Partition == nil || *m.Partition != handlerPartition {
+				t.Errorf("Partition = %v, partition in the handler %v", m.Partition, handlerPartition)
+			}
+			if tt.header != "" && handlerPartition.String() != tt.header {
+				t.Errorf("Partition = %v, want %v from the header", handlerPartition, tt.header)
+			}
+			params := m.Params.(map[string]any)
+			for k, want := range tt.wantParams {
+				if params[k] != want {
+					t.Errorf("Params[%s] = %#v, want %#v", k, params[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddleware_flusher(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	flushed := make(chan struct{})
+	handler := Middleware(l, MiddlewareOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Errorf("The wrapped writer does not implement http.Flusher")
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: first\n\n")
+		flusher.Flush()
+		<-flushed
+		io.WriteString(w, "data: second\n\n")
+	}))
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	buf := make([]byte, len("data: first\n\n"))
+	if _, err := io.ReadFull(resp.Body, buf); err != nil || string(buf) != "data: first\n\n" {
+		t.Errorf("The first event was not flushed: %q, %v", buf, err)
+	}
+	close(flushed)
+	io.ReadAll(resp.Body)
+}
+
+func TestMiddleware_hijacker(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	handler := Middleware(l, MiddlewareOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("The wrapped writer does not implement http.Hijacker")
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+		rw.Flush()
+	}))
+	logged := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		close(logged)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "test")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	resp.Body.Close()
+	<-logged
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("Status = %v, want %v", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	if l.Len() != 1 || l.messages[0].Params.(map[string]any)["status"] != http.StatusSwitchingProtocols {
+		t.Errorf("The hijacked request was not logged with status 101")
+	}
+}
@@ -0,0 +1,52 @@
+package gokibilog
+
+// mergeParams returns params with the defaults added, keys of params win.
+// The defaults can be merged only into params of type map[string]any or nil, otherwise ok is false and params are returned as is.
+// The map of the caller is never modified.
+func mergeParams(params any, defaults map[string]any) (merged any, ok bool) {
+	if len(defaults) == 0 {
+		return params, true
+	}
+	switch p := params.(type) {
+	case nil:
+		m := make(map[string]any, len(defaults))
+		for k, v := range defaults {
+			m[k] = v
+		}
+		return m, true
+	case map[string]any:
+		m := make(map[string]any, len(defaults)+len(p))
+		for k, v := range defaults {
+			m[k] = v
+		}
+		for k, v := range p {
+			m[k] = v
+		}
+		return m, true
+	}
+	return params, false
+}
+
+// addParams returns params with the extra params added like mergeParams, keys of params win.
+// Params of other types are converted by [EncodeParams] first, so structs become maps.
+// Params that are still not a map, for example a slice, are moved under the "params" key next to the extra params.
+func addParams(params any, extra map[string]any) any {
+	if merged, ok := mergeParams(params, extra); ok {
+		return merged
+	}
+	encoded := EncodeParams(params)
+	if merged, ok := mergeParams(encoded, extra); ok {
+		return merged
+	}
+	return wrapParams(encoded, extra)
+}
+
+// wrapParams returns a copy of the extra params with params under the "params" key.
+func wrapParams(params any, extra map[string]any) map[string]any {
+	wrapped := make(map[string]any, len(extra)+1)
+	for k, v := range extra {
+		wrapped[k] = v
+	}
+	wrapped["params"] = params
+	return wrapped
+}
@@ -0,0 +1,117 @@
+package gokibilog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Partition groups messages in Kibilog.com, it is a UUID.
+// It is sent as the canonical lower case text form, for example "550e8400-e29b-11d4-a716-446655440000".
+type Partition [16]byte
+
+// ParsePartition parses the text form of a UUID: the whole string must be 32 hex digits
+// in the 8-4-4-4-12 groups, in any case.
+func ParsePartition(s string) (Partition, error) {
+	if len(s) != 36 {
+		return Partition{}, fmt.Errorf("%w: the length of the UUID is 36, the length of \"%s\" is %d", ErrInvalidPartition, s, len(s))
+	}
+	var p Partition
+	j := 0
+	for i, group := range [5]int{8, 4, 4, 4, 12} {
+		if i > 0 {
+			if s[j] != '-' {
+				return Partition{}, fmt.Errorf("%w: \"%s\"", ErrInvalidPartition, s)
+			}
+			j++
+		}
+		if _, err := hex.Decode(p[(j-i)/2:], []byte(s[j:j+group])); err != nil {
+			return Partition{}, fmt.Errorf("%w: \"%s\"", ErrInvalidPartition, s)
+		}
+		j += group
+	}
+	return p, nil
+}
+
+// MustParsePartition is like [ParsePartition] but panics if the string cannot be parsed.
+func MustParsePartition(s string) Partition {
+	p, err := ParsePartition(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ToPartition converts the value to [Partition]. It accepts:
+//
+// - nil, converted to nil
+//
+// - [Partition] and *Partition
+//
+// - [16]byte with the bytes of the UUID
+//
+// - string and [fmt.Stringer], parsed by [ParsePartition] after trimming spaces
+func ToPartition(v any) (*Partition, error) {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case Partition:
+		return &v, nil
+	case *Partition:
+		if v == nil {
+			return nil, nil
+		}
+		p := *v
+		return &p, nil
+	case [16]byte:
+		p := Partition(v)
+		return &p, nil
+	case string:
+		s = v
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		return nil, fmt.Errorf("%w: partition must be a string, [16]byte, fmt.Stringer or nil, typed %T", ErrInvalidPartition, v)
+	}
+	p, err := ParsePartition(strings.Trim(s, " "))
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// newPartition generates a random UUID v4 for use as a partition.
+func newPartition() Partition {
+	var p Partition
+	if _, err := rand.Read(p[:]); err != nil {
+		panic(fmt.Sprintf("gokibilog: cannot generate UUID: %s", err.Error()))
+	}
+	p[6] = p[6]&0x0f | 0x40
+	p[8] = p[8]&0x3f | 0x80
+	return p
+}
+
+// String returns the canonical lower case text form of the UUID.
+func (p Partition) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", p[0:4], p[4:6], p[6:8], p[8:10], p[10:16])
+}
+
+// IsZero reports whether the partition is the nil UUID.
+func (p Partition) IsZero() bool {
+	return p == Partition{}
+}
+
+func (p Partition) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Partition) UnmarshalText(text []byte) error {
+	parsed, err := ParsePartition(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
@@ -0,0 +1,85 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
+
+func TestToPartition(t *testing.T) {
+	uuid := "550e8400-e29b-11d4-a716-446655440000"
+	partition := MustParsePartition(uuid)
+	tests := []struct {
+		name    string
+		v       any
+		want    string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "nil", v: nil, wantNil: true},
+		{name: "string", v: uuid, want: uuid},
+		{name: "upper case with spaces", v: " 550E8400-E29B-11D4-A716-446655440000 ", want: uuid},
+		{name: "partition", v: partition, want: uuid},
+		{name: "partition pointer", v: &partition, want: uuid},
+		{name: "bytes", v: [16]byte(partition), want: uuid},
+		{name: "stringer", v: stringer(uuid), want: uuid},
+		{name: "embedded in a longer string", v: "id " + uuid, wantErr: true},
+		{name: "no dashes", v: "550e8400e29b11d4a716446655440000", wantErr: true},
+		{name: "misplaced dash", v: "550e840-0e29b-11d4-a716-446655440000", wantErr: true},
+		{name: "not hex", v: "550e8400-e29b-11d4-a716-44665544000J", wantErr: true},
+		{name: "other type", v: 42, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToPartition(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToPartition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidPartition) {
+					t.Errorf("ToPartition() error = %v, want %v", err, ErrInvalidPartition)
+				}
+				return
+			}
+			if (got == nil) != tt.wantNil || got != nil && got.String() != tt.want {
+				t.Errorf("ToPartition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartition_JSON(t *testing.T) {
+	m, _ := NewMessage("test", LevelInfo)
+	m.CreatedAt = nil
+	m.Sequence = 0
+	data, _ := json.Marshal(m)
+	if string(data) != `{"message":"test","createdAt":null,"level":20,"params":null,"partition":null,"sequence":0}` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+
+	m.SetPartition("550E8400-E29B-11D4-A716-446655440000")
+	data, _ = json.Marshal(m)
+	var got Message
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if got.Partition == nil || got.Partition.String() != "550e8400-e29b-11d4-a716-446655440000" {
+		t.Errorf("Partition = %v", got.Partition)
+	}
+}
+
+func Test_newPartition(t *testing.T) {
+	p1, p2 := newPartition(), newPartition()
+	if p1 == p2 {
+		t.Errorf("newPartition() returned the same value twice: %v", p1)
+	}
+	if s := p1.String(); s[14] != '4' {
+		t.Errorf("newPartition() = %v is not UUID v4", s)
+	}
+}
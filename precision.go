@@ -0,0 +1,63 @@
+package gokibilog
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimePrecision defines the unit in which [Message.CreatedAt] is transmitted to Kibilog.com
+type TimePrecision int
+
+// Available precisions of [Message.CreatedAt].
+// PrecisionSecond is used by default, since this is what the Kibilog.com API accepts.
+const (
+	PrecisionSecond TimePrecision = iota
+	PrecisionMillisecond
+	PrecisionMicrosecond
+	PrecisionNanosecond
+)
+
+func (p TimePrecision) String() string {
+	switch p {
+	case PrecisionSecond:
+		return "second"
+	case PrecisionMillisecond:
+		return "millisecond"
+	case PrecisionMicrosecond:
+		return "microsecond"
+	case PrecisionNanosecond:
+		return "nanosecond"
+	}
+	return fmt.Sprintf("TimePrecision(%d)", int(p))
+}
+
+func (p TimePrecision) isValid() bool {
+	return p >= PrecisionSecond && p <= PrecisionNanosecond
+}
+
+// unix converts the time to the Unix timestamp in the unit of the precision.
+func (p TimePrecision) unix(t time.Time) int64 {
+	t = t.UTC()
+	switch p {
+	case PrecisionMillisecond:
+		return t.UnixMilli()
+	case PrecisionMicrosecond:
+		return t.UnixMicro()
+	case PrecisionNanosecond:
+		return t.UnixNano()
+	}
+	return t.Unix()
+}
+
+// time converts the Unix timestamp in the unit of the precision back to the time.
+func (p TimePrecision) time(v int64) time.Time {
+	switch p {
+	case PrecisionMillisecond:
+		return time.UnixMilli(v).UTC()
+	case PrecisionMicrosecond:
+		return time.UnixMicro(v).UTC()
+	case PrecisionNanosecond:
+		return time.Unix(0, v).UTC()
+	}
+	return time.Unix(v, 0).UTC()
+}
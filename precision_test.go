@@ -0,0 +1,89 @@
+package gokibilog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimePrecision_unix(t *testing.T) {
+	createdAt := time.Date(2024, 01, 30, 15, 16, 59, 123456789, time.UTC)
+	tests := []struct {
+		name      string
+		precision TimePrecision
+		want      int64
+	}{
+		{
+			name:      "second",
+			precision: PrecisionSecond,
+			want:      1706627819,
+		},
+		{
+			name:      "millisecond",
+			precision: PrecisionMillisecond,
+			want:      1706627819123,
+		},
+		{
+			name:      "microsecond",
+			precision: PrecisionMicrosecond,
+			want:      1706627819123456,
+		},
+		{
+			name:      "nanosecond",
+			precision: PrecisionNanosecond,
+			want:      1706627819123456789,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.precision.unix(createdAt); got != tt.want {
+				t.Errorf("unix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKibilog_SetTimePrecision(t *testing.T) {
+	k := GetInstance()
+	defer k.SetTimePrecision(PrecisionSecond)
+
+	t.Run("millisecond", func(t *testing.T) {
+		if err := k.SetTimePrecision(PrecisionMillisecond); err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		createdAt := time.Date(2024, 01, 30, 15, 16, 59, 500000000, time.UTC)
+		m, _ := NewMessage("test", LevelDebug)
+		m.SetCreatedAt(createdAt)
+		if *m.CreatedAt != createdAt.UnixMilli() {
+			t.Errorf("SetCreatedAt() = %v, want %v", *m.CreatedAt, createdAt.UnixMilli())
+		}
+	})
+
+	t.Run("changed while buffered", func(t *testing.T) {
+		k.SetTimePrecision(PrecisionSecond)
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+		l.SetMessageTTL(time.Hour)
+		m, _ := NewMessage("test", LevelDebug)
+		createdAt := time.Now().Truncate(time.Second)
+		m.SetCreatedAt(createdAt)
+		l.AddMessage(m)
+
+		k.SetTimePrecision(PrecisionMillisecond)
+		l.removeExpiredMessages()
+		if l.Len() != 1 {
+			t.Fatalf("The message was dropped as expired after the precision change")
+		}
+		sent := correctMessages(l.messages, 0, PrecisionMillisecond)
+		if *sent[0].CreatedAt != createdAt.UnixMilli() {
+			t.Errorf("CreatedAt = %v, want %v", *sent[0].CreatedAt, createdAt.UnixMilli())
+		}
+		if *m.CreatedAt != createdAt.Unix() {
+			t.Errorf("The message in the pool was modified: CreatedAt = %v", *m.CreatedAt)
+		}
+	})
+
+	t.Run("unknown precision", func(t *testing.T) {
+		if err := k.SetTimePrecision(TimePrecision(42)); err == nil {
+			t.Errorf("An unknown precision was set and no error was caused")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package gokibilog
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header.
+const TraceparentHeader = "traceparent"
+
+// PartitionFromTraceparent derives a partition from the trace ID of the W3C traceparent header value.
+//
+// The mapping is deterministic, so all services of a call chain get the same partition:
+// the 16 bytes of the trace ID become a UUID with version 8 and the RFC 4122 variant.
+func PartitionFromTraceparent(traceparent string) (Partition, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return Partition{}, fmt.Errorf("The \"%s\" is not a traceparent", traceparent)
+	}
+	if strings.ToLower(parts[0]) == "ff" {
+		return Partition{}, fmt.Errorf("The traceparent version ff is invalid")
+	}
+	traceId, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return Partition{}, fmt.Errorf("The trace ID of \"%s\" is not hex: %s", traceparent, err.Error())
+	}
+	var p Partition
+	copy(p[:], traceId)
+	if p.IsZero() {
+		return Partition{}, fmt.Errorf("The trace ID of \"%s\" is all zeros", traceparent)
+	}
+	p[6] = p[6]&0x0f | 0x80
+	p[8] = p[8]&0x3f | 0x80
+	return p, nil
+}
+
+// partitionFromRequest extracts the partition from the header of the request,
+// or from traceparent if the header is missing or invalid and fromTraceparent is set.
+func partitionFromRequest(r *http.Request, header string, fromTraceparent bool) (partition Partition, ok bool) {
+	if p, err := ToPartition(r.Header.Get(header)); err == nil && p != nil {
+		return *p, true
+	}
+	if fromTraceparent {
+		if partition, err := PartitionFromTraceparent(r.Header.Get(TraceparentHeader)); err == nil {
+			return partition, true
+		}
+	}
+	return Partition{}, false
+}
+
+// injectPartition returns a copy of the request with the partition of its context set in the header.
+// The request is returned as is if it has no partition or the header is already set.
+func injectPartition(r *http.Request, header string) *http.Request {
+	partition, ok := PartitionFromContext(r.Context())
+	if !ok || r.Header.Get(header) != "" {
+		return r
+	}
+	c := r.Clone(r.Context())
+	c.Header.Set(header, partition.String())
+	return c
+}
@@ -0,0 +1,99 @@
+package gokibilog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPartitionFromTraceparent(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "valid",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:        "4bf92f35-77b3-8da6-a3ce-929d0e0e4736",
+		},
+		{
+			name:        "invalid version",
+			traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantErr:     true,
+		},
+		{
+			name:        "zero trace id",
+			traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			wantErr:     true,
+		},
+		{
+			name:        "not hex",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+			wantErr:     true,
+		},
+		{
+			name:        "empty",
+			traceparent: "",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PartitionFromTraceparent(tt.traceparent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PartitionFromTraceparent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("PartitionFromTraceparent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartitionPropagation(t *testing.T) {
+	serverPool, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	clientPool, _ := NewLogPool("01hggahp9skcph42wknxbckb47")
+
+	srv := httptest.NewServer(Middleware(serverPool, MiddlewareOptions{PartitionFromTraceparent: true})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	))
+	defer srv.Close()
+	client := &http.Client{Transport: NewTransport(clientPool, nil, TransportOptions{})}
+
+	t.Run("header", func(t *testing.T) {
+		partition := "550e8400-e29b-11d4-a716-446655440000"
+		ctx, _ := ContextWithPartition(context.Background(), partition)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		resp.Body.Close()
+		if req.Header.Get(DefaultPartitionHeader) != "" {
+			t.Errorf("The transport modified the original request")
+		}
+
+		got := serverPool.messages[serverPool.Len()-1].Partition
+		if got == nil || got.String() != partition {
+			t.Errorf("Server partition = %v, want %v", got, partition)
+		}
+	})
+
+	t.Run("traceparent", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		resp.Body.Close()
+
+		got := serverPool.messages[serverPool.Len()-1].Partition
+		if got == nil || got.String() != "4bf92f35-77b3-8da6-a3ce-929d0e0e4736" {
+			t.Errorf("Server partition = %v, want the partition of the trace", got)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package gokibilog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// DefaultFlushTimeout is the time [Recover] waits for the messages to be sent.
+const DefaultFlushTimeout = 5 * time.Second
+
+// RecoverOptions configures [RecoverWithOptions] and [RecoverMiddleware].
+type RecoverOptions struct {
+	// FlushTimeout is the deadline of sending the messages after a panic. [DefaultFlushTimeout] if zero.
+	FlushTimeout time.Duration
+	// Repanic makes the panic continue after the messages are sent.
+	// Otherwise, [RecoverWithOptions] stops the panic and [RecoverMiddleware] responds with 500.
+	Repanic bool
+}
+
+// Recover logs a panic as a [LevelCritical] message with the stack trace, sends the messages of [LogPool]
+// and panics again. It must be called directly by defer:
+//
+//	defer gokibilog.Recover(logPool)
+func Recover(pool *LogPool) {
+	if v := recover(); v != nil {
+		handlePanic(context.Background(), pool, v, RecoverOptions{Repanic: true}, nil)
+	}
+}
+
+// RecoverWithOptions works like [Recover], but whether the panic continues depends on the options.
+// It must be called directly by defer:
+//
+//	defer gokibilog.RecoverWithOptions(logPool, gokibilog.RecoverOptions{})
+func RecoverWithOptions(pool *LogPool, opts RecoverOptions) {
+	if v := recover(); v != nil {
+		handlePanic(context.Background(), pool, v, opts, nil)
+	}
+}
+
+// RecoverMiddleware returns net/http middleware that recovers panics of the handler like [Recover].
+// The message gets the partition of the request context, the method and the path.
+// Unless [RecoverOptions.Repanic] is set, the client gets a 500 response.
+func RecoverMiddleware(pool *LogPool, opts RecoverOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				handlePanic(r.Context(), pool, v, opts, map[string]any{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				})
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handlePanic logs and sends the panic value, then panics again if configured.
+func handlePanic(ctx context.Context, pool *LogPool, v any, opts RecoverOptions, extra map[string]any) {
+	params := map[string]any{
+		"panic":     fmt.Sprint(v),
+		"panicType": fmt.Sprintf("%T", v),
+		"stack":     string(debug.Stack()),
+	}
+	if err, ok := v.(error); ok {
+		params["error"] = err.Error()
+	}
+	for k, value := range extra {
+		params[k] = value
+	}
+	if m, err := NewMessage(fmt.Sprintf("panic: %v", v), LevelCritical); err == nil {
+		m.SetParams(params)
+		pool.contextLogger(ctx).AddMessage(m)
+	}
+
+	timeout := opts.FlushTimeout
+	if timeout <= 0 {
+		timeout = DefaultFlushTimeout
+	}
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	if errs := GetInstance().sendPool(flushCtx, pool); len(errs) > 0 {
+		pool.handleError(fmt.Errorf("Error sending messages after panic: %w", errors.Join(errs...)))
+	}
+
+	if opts.Repanic {
+		panic(v)
+	}
+}
@@ -0,0 +1,107 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeKibilog points the client to a local server and collects the received messages.
+func fakeKibilog(t *testing.T) (received func() []Message) {
+	return fakeKibilogWithStatus(t, http.StatusOK)
+}
+
+// fakeKibilogWithStatus is like fakeKibilog, but the server responds with the status.
+func fakeKibilogWithStatus(t *testing.T, status int) (received func() []Message) {
+	var mu sync.Mutex
+	var messages []Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var batch []Message
+		json.Unmarshal(body, &batch)
+		mu.Lock()
+		messages = append(messages, batch...)
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	c := getClientInstance()
+	baseUrl := c.baseUrl
+	c.baseUrl = srv.URL
+	t.Cleanup(func() {
+		c.baseUrl = baseUrl
+		srv.Close()
+	})
+	return func() []Message {
+		mu.Lock()
+		defer mu.Unlock()
+		return messages
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("repanic", func(t *testing.T) {
+		received := fakeKibilog(t)
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+
+		var repanicked any
+		func() {
+			defer func() { repanicked = recover() }()
+			defer Recover(l)
+			panic("boom")
+		}()
+
+		if repanicked != "boom" {
+			t.Errorf("Recover() did not panic again, recovered %v", repanicked)
+		}
+		got := received()
+		if len(got) != 1 || got[0].Level != LevelCritical {
+			t.Fatalf("Received messages = %+v, want one critical message", got)
+		}
+		params := got[0].Params.(map[string]any)
+		if params["panic"] != "boom" || !strings.Contains(params["stack"].(string), "TestRecover") {
+			t.Errorf("Params = %v", params)
+		}
+		if l.Len() != 0 {
+			t.Errorf("LogPool was not flushed, messages count %v", l.Len())
+		}
+	})
+
+	t.Run("swallow", func(t *testing.T) {
+		received := fakeKibilog(t)
+		l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+
+		func() {
+			defer RecoverWithOptions(l, RecoverOptions{})
+			panic(errors.New("boom"))
+		}()
+
+		got := received()
+		if len(got) != 1 || got[0].Params.(map[string]any)["error"] != "boom" {
+			t.Errorf("Received messages = %+v", got)
+		}
+	})
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	received := fakeKibilog(t)
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+
+	handler := RecoverMiddleware(l, RecoverOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/create", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Status = %v, want %v", w.Code, http.StatusInternalServerError)
+	}
+	got := received()
+	if len(got) != 1 || got[0].Params.(map[string]any)["path"] != "/api/user/create" {
+		t.Errorf("Received messages = %+v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package gokibilog
+
+import (
+	"reflect"
+)
+
+// SanitizedKey is the reserved params key where the lenient mode lists the replaced values.
+const SanitizedKey = "_sanitized"
+
+// SetLenientParams enables or disables the lenient mode of params.
+//
+// By default, a message whose params cannot be encoded by "encoding/json" is dropped when sending.
+// In the lenient mode the params are sanitised instead: NaN and Inf become strings, cycles are broken,
+// functions and channels are replaced with type placeholders and the nesting is limited.
+// The message is kept, and what was replaced is listed in the params key [SanitizedKey].
+func (k *Kibilog) SetLenientParams(enabled bool) {
+	k.lenientParams.Store(enabled)
+}
+
+// sanitizeParams returns params that can be encoded by "encoding/json" and the list of replaced values.
+func sanitizeParams(params any) (sanitized any, issues []string) {
+	e := paramsEncoder{lenient: true}
+	sanitized = e.encode(reflect.ValueOf(params), "", 0)
+	return sanitized, e.issues
+}
+
+// sanitizeMessage replaces the params of the message with sanitised ones and lists the replaced values in them.
+func sanitizeMessage(m *Message) {
+	params, issues := sanitizeParams(m.Params)
+	if len(issues) == 0 {
+		m.Params = params
+		return
+	}
+	switch p := params.(type) {
+	case map[string]any:
+		p[SanitizedKey] = issues
+		m.Params = p
+	default:
+		m.Params = map[string]any{
+			"params":     params,
+			SanitizedKey: issues,
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type testNode struct {
+	Name string
+	Next *testNode
+}
+
+func Test_sanitizeParams(t *testing.T) {
+	cyclic := &testNode{Name: "a"}
+	cyclic.Next = &testNode{Name: "b", Next: cyclic}
+
+	selfMap := map[string]any{"name": "root"}
+	selfMap["self"] = selfMap
+
+	tests := []struct {
+		name       string
+		params     any
+		want       any
+		wantIssues []string
+	}{
+		{
+			name:   "valid",
+			params: map[string]any{"orderId": 123456},
+			want:   map[string]any{"orderId": 123456},
+		},
+		{
+			name:       "nan and inf",
+			params:     map[string]any{"ratio": math.NaN(), "limit": []float64{math.Inf(1)}},
+			want:       map[string]any{"ratio": "NaN", "limit": []any{"+Inf"}},
+			wantIssues: []string{"limit[0]: non-finite number replaced with a string", "ratio: non-finite number replaced with a string"},
+		},
+		{
+			name:       "chan and func",
+			params:     map[string]any{"ch": make(chan int), "fn": func() {}},
+			want:       map[string]any{"ch": "<chan int>", "fn": "<func()>"},
+			wantIssues: []string{"ch: chan replaced with a placeholder", "fn: func replaced with a placeholder"},
+		},
+		{
+			name:   "struct cycle",
+			params: cyclic,
+			want: map[string]any{"Name": "a", "Next": map[string]any{
+				"Name": "b", "Next": "<cycle *gokibilog.testNode>",
+			}},
+			wantIssues: []string{"Next.Next: cycle broken"},
+		},
+		{
+			name:       "map cycle",
+			params:     selfMap,
+			want:       map[string]any{"name": "root", "self": "<cycle map[string]interface {}>"},
+			wantIssues: []string{"self: cycle broken"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, issues := sanitizeParams(tt.params)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sanitizeParams() = %#v, want %#v", got, tt.want)
+			}
+			if len(issues) != len(tt.wantIssues) {
+				t.Fatalf("issues = %v, want %v", issues, tt.wantIssues)
+			}
+			for _, want := range tt.wantIssues {
+				found := false
+				for _, issue := range issues {
+					found = found || issue == want
+				}
+				if !found {
+					t.Errorf("issues = %v, want %v", issues, tt.wantIssues)
+				}
+			}
+			if _, err := json.Marshal(got); err != nil {
+				t.Errorf("Sanitised params cannot be encoded: %s", err.Error())
+			}
+		})
+	}
+}
+
+func Test_validatePool_lenient(t *testing.T) {
+	GetInstance().SetLenientParams(true)
+	defer GetInstance().SetLenientParams(false)
+
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	m1, _ := NewMessage("map", LevelInfo)
+	m1.SetParams(map[string]any{"ratio": math.NaN()})
+	m2, _ := NewMessage("array", LevelInfo)
+	m2.SetParams([]any{make(chan int)})
+	l.AddMessage(m1)
+	l.AddMessage(m2)
+
+	if errs, _ := validatePool(l); len(errs) != 0 || l.Len() != 2 {
+		t.Fatalf("validatePool() errors %v, messages count %v, want 0 and 2", errs, l.Len())
+	}
+	if _, ok := l.messages[0].Params.(map[string]any)[SanitizedKey]; !ok {
+		t.Errorf("Params = %v, want %s", l.messages[0].Params, SanitizedKey)
+	}
+	if _, ok := l.messages[1].Params.(map[string]any)["params"]; !ok {
+		t.Errorf("Params = %v, want wrapped params", l.messages[1].Params)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package gokibilog
+
+import "time"
+
+// HandleLevelSignals does nothing on platforms without SIGUSR1 and SIGUSR2.
+func (k *Kibilog) HandleLevelSignals(revertAfter time.Duration) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,50 @@
+//go:build unix
+
+package gokibilog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// HandleLevelSignals steps the minimum levels on signals: SIGUSR1 makes them one level more verbose,
+// SIGUSR2 one level less verbose. The default of [Kibilog] is stepped for [LogPool] that inherit it,
+// the levels set by [LogPool.SetMinLevel] are stepped on their own.
+//
+// If revertAfter is greater than zero, the levels are restored that long after the last signal,
+// except for the levels changed in the meantime, for example by [Kibilog.LevelHandler].
+// Call the returned stop function to stop handling signals, it restores the levels right away.
+// Calling it again does nothing.
+func (k *Kibilog) HandleLevelSignals(revertAfter time.Duration) (stop func()) {
+	s := &levelStepper{k: k, revertAfter: revertAfter}
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == syscall.SIGUSR1 {
+					s.step(-1)
+				} else {
+					s.step(1)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			s.stop()
+		})
+	}
+}
@@ -0,0 +1,37 @@
+//go:build unix
+
+package gokibilog
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestKibilog_HandleLevelSignals(t *testing.T) {
+	k := GetInstance()
+	k.SetMinLevel(LevelInfo)
+	defer k.SetMinLevel(0)
+
+	stop := k.HandleLevelSignals(0)
+	defer stop()
+
+	waitLevel := func(want MessageLevel) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for k.GetMinLevel() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("GetMinLevel() = %v, want %v", k.GetMinLevel(), want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitLevel(LevelDebug)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	waitLevel(LevelInfo)
+
+	stop()
+	stop()
+}
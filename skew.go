@@ -0,0 +1,81 @@
+package gokibilog
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// skewSmoothing is the weight of a new measurement in the moving average of the clock skew.
+const skewSmoothing = 0.2
+
+// skewTracker measures the offset between the local clock and the clock of Kibilog.com
+// using the Date header of the responses.
+type skewTracker struct {
+	mu      sync.Mutex
+	skew    time.Duration
+	known   bool
+	correct bool
+}
+
+// observe registers the Date header of the response received between sentAt and receivedAt.
+func (s *skewTracker) observe(header http.Header, sentAt, receivedAt time.Time) {
+	serverTime, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return
+	}
+	// The Date header is truncated to seconds, on average the server time is half a second later.
+	serverTime = serverTime.Add(500 * time.Millisecond)
+	localTime := sentAt.Add(receivedAt.Sub(sentAt) / 2)
+	sample := serverTime.Sub(localTime)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.known {
+		s.skew = sample
+		s.known = true
+		return
+	}
+	s.skew += time.Duration(float64(sample-s.skew) * skewSmoothing)
+}
+
+func (s *skewTracker) get() (skew time.Duration, known bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skew, s.known
+}
+
+func (s *skewTracker) setCorrection(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.correct = enabled
+}
+
+// correction returns the offset that has to be added to CreatedAt before the upload.
+func (s *skewTracker) correction() (skew time.Duration, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skew, s.correct && s.known
+}
+
+// correctMessages returns copies of the messages with CreatedAt shifted by the skew
+// and converted to the precision. Messages that need no change are kept as is.
+func correctMessages(messages []*Message, skew time.Duration, precision TimePrecision) []*Message {
+	corrected := make([]*Message, 0, len(messages))
+	for _, m := range messages {
+		createdAt, ok := time.Time{}, false
+		if m != nil {
+			createdAt, ok = m.createdAtTime()
+		}
+		if !ok || skew == 0 && m.precision == precision {
+			corrected = append(corrected, m)
+			continue
+		}
+		c := *m
+		createdAtInt := precision.unix(createdAt.Add(skew))
+		c.CreatedAt = &createdAtInt
+		c.precision = precision
+		corrected = append(corrected, &c)
+	}
+	return corrected
+}
@@ -0,0 +1,109 @@
+package gokibilog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_skewTracker_observe(t *testing.T) {
+	local := time.Date(2024, 01, 30, 15, 16, 59, 0, time.UTC)
+	tests := []struct {
+		name    string
+		dates   []time.Time
+		want    time.Duration
+		wantOk  bool
+		invalid bool
+	}{
+		{
+			name:   "single",
+			dates:  []time.Time{local.Add(time.Hour)},
+			want:   time.Hour + 500*time.Millisecond,
+			wantOk: true,
+		},
+		{
+			name:   "smoothed",
+			dates:  []time.Time{local.Add(10 * time.Second), local.Add(20 * time.Second)},
+			want:   12*time.Second + 500*time.Millisecond,
+			wantOk: true,
+		},
+		{
+			name:    "invalid header",
+			wantOk:  false,
+			invalid: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s skewTracker
+			for _, d := range tt.dates {
+				h := http.Header{}
+				h.Set("Date", d.Format(http.TimeFormat))
+				s.observe(h, local, local)
+			}
+			if tt.invalid {
+				h := http.Header{}
+				h.Set("Date", "yesterday")
+				s.observe(h, local, local)
+			}
+			got, ok := s.get()
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("get() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestKibilog_SetClockSkewCorrection(t *testing.T) {
+	local := time.Date(2024, 01, 30, 15, 16, 59, 0, time.UTC)
+	server := local.Add(time.Hour)
+
+	var received []Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = nil
+		json.Unmarshal(body, &received)
+		w.Header().Set("Date", server.Format(http.TimeFormat))
+	}))
+	defer srv.Close()
+
+	c := getClientInstance()
+	baseUrl := c.baseUrl
+	c.baseUrl = srv.URL
+	c.skew = skewTracker{}
+	k := GetInstance()
+	k.SetClock(NewManualClock(local))
+	defer func() {
+		c.baseUrl = baseUrl
+		c.skew = skewTracker{}
+		k.SetClock(nil)
+	}()
+
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	m, _ := NewMessage("test", LevelDebug)
+	l.AddMessage(m)
+	if _, err := c.Send(context.Background(), l, l.messages, nil); err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if skew, ok := k.GetClockSkew(); !ok || skew != time.Hour+500*time.Millisecond {
+		t.Errorf("GetClockSkew() = %v, %v, want %v, true", skew, ok, time.Hour+500*time.Millisecond)
+	}
+	if *received[0].CreatedAt != local.Unix() {
+		t.Errorf("CreatedAt without correction = %v, want %v", *received[0].CreatedAt, local.Unix())
+	}
+
+	k.SetClockSkewCorrection(true)
+	if _, err := c.Send(context.Background(), l, l.messages, nil); err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	if want := local.Add(time.Hour).Unix(); *received[0].CreatedAt != want {
+		t.Errorf("CreatedAt with correction = %v, want %v", *received[0].CreatedAt, want)
+	}
+	if *m.CreatedAt != local.Unix() {
+		t.Errorf("Correction modified the message in LogPool: %v, want %v", *m.CreatedAt, local.Unix())
+	}
+}
@@ -0,0 +1,183 @@
+package gokibilog
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRedactedQueryParams are query parameters whose values are hidden by [NewTransport] unless configured otherwise.
+var DefaultRedactedQueryParams = []string{"token", "access_token", "api_key", "apikey", "key", "password", "secret", "signature"}
+
+const redacted = "REDACTED"
+
+// TransportOptions configures [NewTransport].
+type TransportOptions struct {
+	// RedactQueryParams are query parameters whose values are replaced with "REDACTED" in the logged URL,
+	// case-insensitively. [DefaultRedactedQueryParams] if nil.
+	RedactQueryParams []string
+	// MaxRetries is the number of retries of idempotent requests that failed with an error or a 5xx status.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for each next one.
+	RetryBackoff time.Duration
+	// PartitionHeader is the header the partition of the request context is sent in,
+	// so that [Middleware] of the called service logs into the same partition. [DefaultPartitionHeader] if empty.
+	PartitionHeader string
+	// DisablePartitionPropagation stops sending the partition header.
+	DisablePartitionPropagation bool
+}
+
+type transport struct {
+	pool   *LogPool
+	next   http.RoundTripper
+	opts   TransportOptions
+	redact map[string]bool
+}
+
+// NewTransport wraps [http.RoundTripper] so that every outgoing request is logged into [LogPool].
+// If next is nil, [http.DefaultTransport] is used.
+//
+// The message contains the method, the URL with redacted query, the status, the latency, the error and the number of retries.
+// Its level depends on the result: [LevelError] for errors and 5xx, [LevelWarning] for 4xx, [LevelInfo] otherwise.
+// The partition is taken from the request context, see [ContextWithPartition], and sent in the partition header.
+func NewTransport(pool *LogPool, next http.RoundTripper, opts TransportOptions) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if opts.PartitionHeader == "" {
+		opts.PartitionHeader = DefaultPartitionHeader
+	}
+	if opts.RedactQueryParams == nil {
+		opts.RedactQueryParams = DefaultRedactedQueryParams
+	}
+	t := &transport{pool: pool, next: next, opts: opts, redact: map[string]bool{}}
+	for _, param := range opts.RedactQueryParams {
+		t.redact[strings.ToLower(param)] = true
+	}
+	return t
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clock := GetInstance().GetClock()
+	start := clock.Now()
+	if !t.opts.DisablePartitionPropagation {
+		req = injectPartition(req, t.opts.PartitionHeader)
+	}
+
+	var resp *http.Response
+	var err error
+	retries := 0
+	for {
+		attempt := req
+		if retries > 0 {
+			attempt, err = rewindRequest(req)
+			if err != nil {
+				// The previous response is already closed.
+				resp = nil
+				break
+			}
+		}
+		resp, err = t.next.RoundTrip(attempt)
+		if !t.shouldRetry(req, resp, err, retries) {
+			break
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if !sleepContext(req, t.opts.RetryBackoff<<retries) {
+			resp, err = nil, req.Context().Err()
+			break
+		}
+		retries++
+	}
+
+	params := map[string]any{
+		"method":     req.Method,
+		"url":        t.redactURL(req.URL),
+		"durationMs": float64(clock.Now().Sub(start).Microseconds()) / 1000,
+	}
+	if retries > 0 {
+		params["retries"] = retries
+	}
+	level := LevelError
+	if err != nil {
+		params["error"] = err.Error()
+	} else {
+		params["status"] = resp.StatusCode
+		level = levelByStatus(resp.StatusCode)
+	}
+	if m := t.pool.newMessage(level, req.Method+" "+req.URL.Host+req.URL.Path, nil); m != nil {
+		m.SetParams(params)
+		t.pool.contextLogger(req.Context()).AddMessage(m)
+	}
+
+	return resp, err
+}
+
+func (t *transport) shouldRetry(req *http.Request, resp *http.Response, err error, retries int) bool {
+	if retries >= t.opts.MaxRetries || req.Context().Err() != nil {
+		return false
+	}
+	if err == nil && resp.StatusCode < 500 {
+		return false
+	}
+	if !isIdempotent(req.Method) {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// redactURL returns the URL without user info and with the values of the redacted query parameters hidden.
+func (t *transport) redactURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	if c.RawQuery != "" {
+		query := c.Query()
+		for param := range query {
+			if t.redact[strings.ToLower(param)] {
+				query[param] = []string{redacted}
+			}
+		}
+		c.RawQuery = query.Encode()
+	}
+	return c.String()
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// rewindRequest returns a copy of the request with a fresh body for a retry.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	c := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		c.Body = body
+	}
+	return c, nil
+}
+
+// sleepContext waits for d and returns false if the request context is done earlier.
+func sleepContext(req *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-req.Context().Done():
+		return false
+	}
+}
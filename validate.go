@@ -0,0 +1,146 @@
+package gokibilog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors of the message fields, use errors.Is with the error of [Message.Validate].
+var (
+	ErrEmptyMessage      = errors.New("the message cannot be empty")
+	ErrUnknownLevel      = errors.New("unknown level")
+	ErrInvalidPartition  = errors.New("the partition is not a UUID")
+	ErrOversize          = errors.New("the value is too large")
+	ErrUnencodableParams = errors.New("the params cannot be encoded")
+	ErrInvalidUTF8       = errors.New("invalid UTF-8")
+)
+
+// FieldError describes a problem with one field of [Message].
+type FieldError struct {
+	// Field is the JSON name of the field, for example "partition".
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err.Error())
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists all problems found by [Message.Validate].
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Error())
+	}
+	return "invalid message: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// oversizeOnly reports whether all problems are about the size.
+func (e *ValidationError) oversizeOnly() bool {
+	for _, f := range e.Fields {
+		if !errors.Is(f.Err, ErrOversize) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks all fields of [Message] and returns [*ValidationError] listing every failing field:
+// an empty, oversized or invalid UTF-8 text, an unknown level,
+// params that cannot be encoded or contain invalid UTF-8, and a message larger than [Kibilog.SetMaxMessageBytes].
+//
+// The text is checked against [Limits.MaxMessageRunes] of [Kibilog]. [LogPool.AddMessage] truncates
+// too long texts instead, so messages in a pool are only rejected for the other reasons.
+func (m *Message) Validate() error {
+	k := GetInstance()
+	maxRunes := 0
+	if limits := k.limits.Load(); limits != nil {
+		maxRunes = limits.MaxMessageRunes
+	}
+	return m.validate(maxRunes, k.maxMessageBytes.Load(), true)
+}
+
+// validate checks the message with the size limits, zero limits are not checked.
+// Without params, the encoding of params and the total size are not checked, which is much cheaper.
+func (m *Message) validate(maxRunes int, maxBytes int64, params bool) error {
+	var fields []*FieldError
+	add := func(field string, err error) {
+		fields = append(fields, &FieldError{Field: field, Err: err})
+	}
+
+	if strings.Trim(m.Message, "\r\n\t ") == "" {
+		add("message", ErrEmptyMessage)
+	}
+	if !utf8.ValidString(m.Message) {
+		add("message", ErrInvalidUTF8)
+	}
+	if maxRunes > 0 {
+		if n := utf8.RuneCountInString(m.Message); n > maxRunes {
+			add("message", fmt.Errorf("%w: %d runes, the limit is %d", ErrOversize, n, maxRunes))
+		}
+	}
+	if !m.Level.isValid() {
+		add("level", fmt.Errorf("%w %d", ErrUnknownLevel, int(m.Level)))
+	}
+
+	if params {
+		c := *m
+		c.Params = EncodeParams(m.Params)
+		// The other fields always encode, so an error comes from params.
+		if body, err := json.Marshal(c); err != nil {
+			add("params", fmt.Errorf("%w: %s", ErrUnencodableParams, err.Error()))
+		} else {
+			if !validUTF8(c.Params) {
+				add("params", ErrInvalidUTF8)
+			}
+			if maxBytes > 0 && int64(len(body)) > maxBytes {
+				add("message", fmt.Errorf("%w: %d bytes, the limit is %d", ErrOversize, len(body), maxBytes))
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// validUTF8 reports whether all strings and keys in params converted by [EncodeParams] are valid UTF-8.
+func validUTF8(v any) bool {
+	switch value := v.(type) {
+	case string:
+		return utf8.ValidString(value)
+	case map[string]any:
+		for k, element := range value {
+			if !utf8.ValidString(k) || !validUTF8(element) {
+				return false
+			}
+		}
+	case []any:
+		for _, element := range value {
+			if !validUTF8(element) {
+				return false
+			}
+		}
+	}
+	return true
+}
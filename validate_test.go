@@ -0,0 +1,120 @@
+package gokibilog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	partition := MustParsePartition("b8e8f30e-0da0-4c9e-a1a5-7f7b2c1d3e4f")
+	tests := []struct {
+		name       string
+		message    Message
+		wantFields []string
+		wantErr    error
+	}{
+		{
+			name:    "valid",
+			message: Message{Message: "test", Level: LevelInfo, Partition: &partition},
+		},
+		{
+			name:       "empty",
+			message:    Message{Message: " \n", Level: LevelInfo},
+			wantFields: []string{"message"},
+			wantErr:    ErrEmptyMessage,
+		},
+		{
+			name:       "invalid UTF-8",
+			message:    Message{Message: "test \xff", Level: LevelInfo},
+			wantFields: []string{"message"},
+			wantErr:    ErrInvalidUTF8,
+		},
+		{
+			name:       "unknown level",
+			message:    Message{Message: "test", Level: 42},
+			wantFields: []string{"level"},
+			wantErr:    ErrUnknownLevel,
+		},
+		{
+			name:       "unencodable params",
+			message:    Message{Message: "test", Level: LevelInfo, Params: map[string]any{"ch": make(chan int)}},
+			wantFields: []string{"params"},
+			wantErr:    ErrUnencodableParams,
+		},
+		{
+			name:       "invalid UTF-8 params",
+			message:    Message{Message: "test", Level: LevelInfo, Params: map[string]any{"body": "\xff"}},
+			wantFields: []string{"params"},
+			wantErr:    ErrInvalidUTF8,
+		},
+		{
+			name:       "several fields",
+			message:    Message{Message: "", Level: 42},
+			wantFields: []string{"message", "level"},
+			wantErr:    ErrUnknownLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.message.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %T, want *ValidationError", err)
+			}
+			var fields []string
+			for _, f := range validationErr.Fields {
+				fields = append(fields, f.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestMessage_Validate_oversize(t *testing.T) {
+	k := GetInstance()
+	k.SetLimits(Limits{MaxMessageRunes: 4})
+	defer k.SetLimits(Limits{})
+
+	m := Message{Message: "test message", Level: LevelInfo}
+	err := m.Validate()
+	if !errors.Is(err, ErrOversize) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrOversize)
+	}
+}
+
+func TestLogPool_AddMessage_invalid(t *testing.T) {
+	l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+	var got error
+	l.SetErrorHandler(func(err error) {
+		got = err
+	})
+
+	l.AddMessage(&Message{Message: "test", Level: 42})
+	if l.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", l.Len())
+	}
+	if !errors.Is(got, ErrUnknownLevel) {
+		t.Errorf("error handler got %v, want %v", got, ErrUnknownLevel)
+	}
+}
+
+func TestNewMessage_invalid(t *testing.T) {
+	if _, err := NewMessage("test", 42); !errors.Is(err, ErrUnknownLevel) {
+		t.Errorf("NewMessage() error = %v, want %v", err, ErrUnknownLevel)
+	}
+	if _, err := NewMessage(" ", LevelInfo); !errors.Is(err, ErrEmptyMessage) {
+		t.Errorf("NewMessage() error = %v, want %v", err, ErrEmptyMessage)
+	}
+}
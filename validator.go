@@ -1,19 +1,19 @@
 package gokibilog
 
-import (
-	"encoding/json"
-	"fmt"
-)
-
-func validatePool(pool *LogPool) (errs []error) {
-	for k, m := range pool.messages {
-		_, err := json.Marshal(m)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("Error in the message for \"%s\": %s", pool.logId, err.Error()))
-			pool.messages[k] = nil
+// validatePool removes invalid messages from [LogPool] and returns them to be dead-lettered after unlocking.
+// The caller must hold the lock.
+func validatePool(pool *LogPool) (errs []error, invalid []pendingLetter) {
+	for i, m := range pool.messages {
+		if m == nil {
+			continue
+		}
+		if reason, err := pool.checkMessage(m); err != nil {
+			errs = append(errs, err)
+			invalid = append(invalid, pendingLetter{message: m, reason: reason, err: err})
+			pool.messages[i] = nil
 		}
 	}
 	pool.removeNilMessages()
 
-	return errs
+	return errs, invalid
 }
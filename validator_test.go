@@ -43,11 +43,28 @@ func Test_validatePool(t *testing.T) {
 			wantErrsCount: 0,
 			messageCount:  1,
 		},
+		{
+			name: "changed after add",
+			args: args{
+				pool: func() *LogPool {
+					l, _ := NewLogPool("01hggahp9skcph42wknxbckb46")
+					l.SetErrorHandler(func(error) {})
+					m1, _ := NewMessage("test 1", LevelInfo)
+					m2, _ := NewMessage("test 2", LevelInfo)
+					l.AddMessage(m1)
+					l.AddMessage(m2)
+					m2.Level = 42
+					return l
+				},
+			},
+			wantErrsCount: 1,
+			messageCount:  1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			logPool := tt.args.pool()
-			gotErrs := validatePool(logPool)
+			gotErrs, _ := validatePool(logPool)
 
 			if len(gotErrs) != tt.wantErrsCount || tt.messageCount != logPool.Len() {
 				t.Errorf("validatePool(). Erros count = %v, want %v. Messages count %v, want %v.", len(gotErrs), tt.wantErrsCount, logPool.Len(), tt.messageCount)